@@ -0,0 +1,101 @@
+package progresscli
+
+import (
+    "bufio"
+    "bytes"
+    "io"
+    "regexp"
+)
+
+// GoBuildPattern matches the tool invocations echoed by `go build -x`.
+// Each compile, link, asm or cgo action counts as one unit of work and
+// the name of the tool is used as the label.
+var GoBuildPattern = regexp.MustCompile(
+    `^\S*/pkg/tool/[^/\s]+/(?P<label>compile|link|asm|cgo|pack)\s`)
+
+// MakePattern matches the short target echoes commonly printed by
+// Makefiles (e.g. "  CC      foo.o"). The target name is used as the
+// label.
+var MakePattern = regexp.MustCompile(
+    `^\s*(?:CC|CXX|LD|AR|AS|GEN|HOSTCC|HOSTLD)\s+(?P<label>\S+)`)
+
+// LineAdapter converts the line oriented output of an external tool
+// into progress bar updates. Every line matching the adapter's
+// pattern increments the bar by one, all other lines are discarded.
+// If the pattern has a capture group named "label", the captured text
+// is used as the bar's label.
+//
+// A LineAdapter implements io.Writer, so it can be assigned directly
+// to the Stdout or Stderr field of an exec.Cmd.
+type LineAdapter struct {
+    bar     *ProgressBar
+    pattern *regexp.Regexp
+    label   int
+    buf     []byte
+}
+
+// NewLineAdapter will create a new LineAdapter that drives the
+// specified progress bar using the specified pattern.
+func NewLineAdapter(pb *ProgressBar, pattern *regexp.Regexp) *LineAdapter {
+    return &LineAdapter{
+        bar: pb,
+        pattern: pattern,
+        label: pattern.SubexpIndex("label"),
+    }
+}
+
+// NewGoBuildAdapter will create a new LineAdapter for the output of
+// `go build -x`. Since the toolchain does not report the total number
+// of actions up front, you should set the max value of the progress
+// bar yourself, for example to the number of packages reported by
+// `go list -deps`.
+func NewGoBuildAdapter(pb *ProgressBar) *LineAdapter {
+    return NewLineAdapter(pb, GoBuildPattern)
+}
+
+// NewMakeAdapter will create a new LineAdapter for the output of make
+// using the MakePattern.
+func NewMakeAdapter(pb *ProgressBar) *LineAdapter {
+    return NewLineAdapter(pb, MakePattern)
+}
+
+// Write implements io.Writer. Partial lines are buffered until the
+// rest of the line has been written.
+func (a *LineAdapter) Write(p []byte) (int, error) {
+    a.buf = append(a.buf, p...)
+    for {
+        i := bytes.IndexByte(a.buf, '\n')
+        if i < 0 {
+            break
+        }
+
+        a.handleLine(string(a.buf[:i]))
+        a.buf = a.buf[i+1:]
+    }
+
+    return len(p), nil
+}
+
+// Watch will read from r until EOF, updating the progress bar for
+// each matching line.
+func (a *LineAdapter) Watch(r io.Reader) error {
+    scanner := bufio.NewScanner(r)
+    for scanner.Scan() {
+        a.handleLine(scanner.Text())
+    }
+
+    return scanner.Err()
+}
+
+func (a *LineAdapter) handleLine(line string) {
+    m := a.pattern.FindStringSubmatch(line)
+    if m == nil {
+        return
+    }
+
+    if a.label >= 0 && m[a.label] != "" {
+        a.bar.SetLabel(m[a.label])
+    }
+
+    a.bar.Increment(1)
+}
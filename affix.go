@@ -0,0 +1,52 @@
+package progresscli
+
+import (
+    "strings"
+)
+
+// SetPrefix will set a static string displayed before every frame of
+// the progress bar, such as a log-style "[sync] " tag, instead of
+// placing it in the label. Its width is taken from the width available
+// to the rest of the frame. If the bar wraps across several rows, each
+// row is prefixed.
+func (pb *ProgressBar) SetPrefix(prefix string) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    pb.prefix = prefix
+    pb.update()
+}
+
+// SetSuffix will set a static string displayed after every frame of
+// the progress bar. Its width is taken from the width available to the
+// rest of the frame. If the bar wraps across several rows, only the
+// first row is followed by the suffix.
+func (pb *ProgressBar) SetSuffix(suffix string) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    pb.suffix = suffix
+    pb.update()
+}
+
+// affixWidth will measure the number of columns taken up by the prefix
+// and suffix of the progress bar. The caller must hold pb.mu.
+func (pb *ProgressBar) affixWidth() int {
+    return VisibleWidth(pb.prefix) + VisibleWidth(pb.suffix)
+}
+
+// affix will place the prefix and suffix of the progress bar around a
+// frame. The caller must hold pb.mu.
+func (pb *ProgressBar) affix(frame string) string {
+    if pb.prefix == "" && pb.suffix == "" {
+        return frame
+    }
+
+    rows := strings.Split(frame, "\n")
+    rows[0] += pb.suffix
+    for i := range rows {
+        rows[i] = pb.prefix + rows[i]
+    }
+
+    return strings.Join(rows, "\n")
+}
@@ -0,0 +1,51 @@
+package progresscli
+
+import (
+    "os"
+    "strings"
+)
+
+// DetectLineMode will guess the line mode suited to the terminal from
+// the TERM environment variable. Output panes of IDEs such as
+// JetBrains IDEs and VS Code, as well as other consoles reporting
+// themselves as "dumb", often ignore carriage returns, so Backspace
+// is used for them. Overwrite is used otherwise.
+func DetectLineMode() LineMode {
+    if os.Getenv("TERM") == "dumb" {
+        return Backspace
+    }
+
+    return Overwrite
+}
+
+// backspaceFrame will build the sequence drawing a frame over the one
+// drawn previously by moving back over it using backspaces. If the
+// frame is narrower than the previous one, the rest of the previous
+// frame is blanked out. Rows of the frame are joined into a single
+// line. The caller must hold pb.mu.
+func (pb *ProgressBar) backspaceFrame(output string) string {
+    output = strings.ReplaceAll(output, "\n", " ")
+    width := VisibleWidth(output)
+
+    var b strings.Builder
+    b.WriteString(strings.Repeat("\b", pb.drawnWidth))
+    b.WriteString(output)
+    if excess := pb.drawnWidth - width; excess > 0 {
+        b.WriteString(strings.Repeat(" ", excess))
+        b.WriteString(strings.Repeat("\b", excess))
+    }
+
+    pb.drawnWidth = width
+    return b.String()
+}
+
+// backspaceClear will build the sequence blanking out the frame drawn
+// previously using backspaces, leaving the cursor at its start. The
+// caller must hold pb.mu.
+func (pb *ProgressBar) backspaceClear() string {
+    back := strings.Repeat("\b", pb.drawnWidth)
+    blank := strings.Repeat(" ", pb.drawnWidth)
+
+    pb.drawnWidth = 0
+    return back + blank + back
+}
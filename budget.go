@@ -0,0 +1,109 @@
+package progresscli
+
+import (
+    "fmt"
+    "strings"
+)
+
+// SetLineBudget will limit the number of terminal lines the manager's
+// rows may take up, for applications embedding the manager below a
+// TUI of their own that owns the rest of the screen. When the rows
+// don't fit, bars wrapping across several rows are collapsed to their
+// first row, and if that is not enough, bars that are still running
+// are displayed in preference to ones that have ended and the rest
+// are summarized on the last line, e.g. "+4 more: 1 running, 3 done".
+// The summary row set using SetSummaryRow() is only kept if the budget
+// leaves room for at least one other line. Zero, the default, removes
+// the limit.
+func (m *Manager) SetLineBudget(lines int) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    if lines < 0 {
+        lines = 0
+    }
+
+    m.lineBudget = lines
+    m.requestDraw()
+}
+
+// Lines will retrieve the number of terminal lines the manager's rows
+// took up when they were last drawn, so that an embedding application
+// can lay out the rest of the screen around them.
+func (m *Manager) Lines() int {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    return m.lines
+}
+
+// rows will build the lines drawn by the manager, fitting them in its
+// line budget. The caller must hold m.mu.
+func (m *Manager) rows() []string {
+    frames := make([][]string, len(m.bars))
+    total := 0
+    for i, pb := range m.bars {
+        frames[i] = strings.Split(m.frames[pb], "\n")
+        total += len(frames[i])
+    }
+
+    var extra []string
+    if m.summaryRow != nil {
+        extra = append(extra, m.summaryRow(m.currentThroughput()))
+    }
+
+    budget := m.lineBudget
+    if budget > 0 && total+len(extra) > budget {
+        if len(extra) >= budget {
+            extra = nil
+        }
+        frames = m.fitRows(frames, budget-len(extra))
+    }
+
+    var lines []string
+    for _, rows := range frames {
+        lines = append(lines, rows...)
+    }
+
+    return append(lines, extra...)
+}
+
+// fitRows will fit the frames of the manager's progress bars in the
+// specified number of lines, see SetLineBudget(). The caller must hold
+// m.mu.
+func (m *Manager) fitRows(frames [][]string, budget int) [][]string {
+    for i := range frames {
+        frames[i] = frames[i][:1]
+    }
+
+    if len(frames) <= budget {
+        return frames
+    }
+
+    // One line is taken up by the summary of the bars left out.
+    shown := map[int]bool{}
+    for _, ended := range []bool{false, true} {
+        for i, pb := range m.bars {
+            if len(shown) < budget-1 && m.ends[pb] == ended {
+                shown[i] = true
+            }
+        }
+    }
+
+    var fitted [][]string
+    var running, done int
+    for i, pb := range m.bars {
+        switch {
+        case shown[i]:
+            fitted = append(fitted, frames[i])
+        case m.ends[pb]:
+            done++
+        default:
+            running++
+        }
+    }
+
+    summary := fmt.Sprintf("+%d more: %d running, %d done",
+        running+done, running, done)
+    return append(fitted, []string{summary})
+}
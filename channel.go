@@ -0,0 +1,53 @@
+package progresscli
+
+import (
+    "fmt"
+    "time"
+)
+
+// gaugeInterval is the interval at which the fill level of a watched
+// channel is sampled.
+const gaugeInterval = 100 * time.Millisecond
+
+// WatchChannel will create a progress bar displaying the number of
+// items buffered in ch as a live gauge of its capacity, for example
+// "[#####-----]  50% 32/64 queued". A channel that is always full or
+// always empty shows at a glance whether the producers or the
+// consumers of a pipeline are the bottleneck. If capacity is zero or
+// negative, the capacity of the channel is used.
+//
+// The fill level is sampled while the progress bar is visible. Like a
+// status line, the gauge never finishes on its own; call Abort(), or
+// FinishAll() on its manager, once the pipeline is done.
+func WatchChannel[T any](ch <-chan T, capacity int) *ProgressBar {
+    if capacity <= 0 {
+        capacity = cap(ch)
+    }
+
+    pb := New()
+    pb.max = float64(capacity)
+    pb.gauge = func() int {
+        return len(ch)
+    }
+    pb.AddDecorator(func(s State) string {
+        return fmt.Sprintf("%.0f/%.0f queued", s.Value, s.Max)
+    })
+
+    return pb
+}
+
+// sampleGauge will sample the fill level of a watched channel and
+// redraw the progress bar if it changed. The caller must hold pb.mu.
+func (pb *ProgressBar) sampleGauge() {
+    if pb.gauge == nil {
+        return
+    }
+
+    value := float64(pb.gauge())
+    if value == pb.value {
+        return
+    }
+
+    pb.value = value
+    pb.update()
+}
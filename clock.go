@@ -0,0 +1,131 @@
+package progresscli
+
+import (
+    "sync"
+    "time"
+)
+
+// Clock provides the current time and tickers to a progress bar.
+// Everything that depends on time, such as the ETA and elapsed
+// decorators, the refresh interval and spinner animation, reads it
+// from the progress bar's clock. Injecting a fake clock, such as the
+// one in the progresstest package, makes time dependent behavior
+// testable.
+type Clock interface {
+    Now() time.Time
+    NewTicker(d time.Duration) Ticker
+}
+
+// Ticker delivers ticks at intervals, like a time.Ticker.
+type Ticker interface {
+    C() <-chan time.Time
+    Stop()
+}
+
+// timeTicker adapts a time.Ticker to the Ticker interface.
+type timeTicker struct {
+    ticker *time.Ticker
+}
+
+func (t timeTicker) C() <-chan time.Time {
+    return t.ticker.C
+}
+
+func (t timeTicker) Stop() {
+    t.ticker.Stop()
+}
+
+// FrameClock is a Clock that only advances when a frame is rendered,
+// and then by a fixed interval. Progress bars using a FrameClock
+// produce the same output every time they are run, which makes them
+// suitable for recordings and documentation. A frame clock may be
+// shared by several progress bars, in which case it advances whenever
+// any of them renders a frame. You should initialize a new frame clock
+// using the NewFrameClock() function.
+type FrameClock struct {
+    mu       sync.Mutex
+    now      time.Time
+    interval time.Duration
+}
+
+// NewFrameClock will create a new FrameClock starting at the
+// specified time and advancing by the specified interval per frame.
+func NewFrameClock(start time.Time, interval time.Duration) *FrameClock {
+    return &FrameClock{
+        now: start,
+        interval: interval,
+    }
+}
+
+// Now will retrieve the current time of the frame clock.
+func (c *FrameClock) Now() time.Time {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+
+    return c.now
+}
+
+// NewTicker will create a new Ticker. Frames are still delivered in
+// real time, only the time reported by the clock is deterministic.
+func (c *FrameClock) NewTicker(d time.Duration) Ticker {
+    return timeTicker{time.NewTicker(d)}
+}
+
+// nextFrame will advance the frame clock by one frame.
+func (c *FrameClock) nextFrame() {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+
+    c.now = c.now.Add(c.interval)
+}
+
+// SetClock will set the clock used by the progress bar. Passing nil
+// restores the default wall-clock. If the progress bar is running, its
+// refresh ticker is restarted using the new clock.
+func (pb *ProgressBar) SetClock(clock Clock) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    if pb.tickerDone == nil {
+        pb.clock = clock
+        return
+    }
+
+    pb.stopTicker()
+    pb.clock = clock
+    pb.startTicker()
+}
+
+// SetDeterministic will enable the deterministic rendering mode. In
+// this mode the progress bar uses a FrameClock starting at the Unix
+// epoch which advances by the specified interval each time a frame is
+// rendered, so the output of a run, including time based decorators,
+// is reproducible. This should be called before the progress bar is
+// shown.
+func (pb *ProgressBar) SetDeterministic(interval time.Duration) {
+    pb.SetClock(NewFrameClock(time.Unix(0, 0).UTC(), interval))
+}
+
+// now will retrieve the current time from the progress bar's clock.
+func (pb *ProgressBar) now() time.Time {
+    if pb.clock == nil {
+        return time.Now()
+    }
+
+    return pb.clock.Now()
+}
+
+// newTicker will create a new Ticker using the progress bar's clock.
+func (pb *ProgressBar) newTicker(d time.Duration) Ticker {
+    if pb.clock == nil {
+        return timeTicker{time.NewTicker(d)}
+    }
+
+    return pb.clock.NewTicker(d)
+}
+
+// since will retrieve the time elapsed since t according to the
+// progress bar's clock.
+func (pb *ProgressBar) since(t time.Time) time.Duration {
+    return pb.now().Sub(t)
+}
@@ -0,0 +1,66 @@
+package progresscli
+
+import (
+    "strings"
+)
+
+// ColorProvider reports whether colored output is enabled. It allows a
+// progress bar to follow the color setting of the color library used
+// by the rest of a program, so that disabling colors there, for
+// example with a --no-color flag or the NO_COLOR environment variable,
+// disables them for the progress bar as well. Adapters for
+// github.com/fatih/color and github.com/muesli/termenv are provided by
+// the fatihcolor and termenvcolor packages.
+type ColorProvider interface {
+    ColorEnabled() bool
+}
+
+// ColorFunc is an adapter allowing an ordinary function to be used as
+// a ColorProvider.
+type ColorFunc func() bool
+
+// ColorEnabled will call f.
+func (f ColorFunc) ColorEnabled() bool {
+    return f()
+}
+
+// SetColorProvider will set the ColorProvider consulted each time a
+// frame is rendered. While it reports colors as disabled, the progress
+// bar is drawn using its style without colors, see WithoutColor().
+// Passing nil always draws the style as it is, which is the default.
+func (pb *ProgressBar) SetColorProvider(p ColorProvider) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    pb.colors = p
+    pb.update()
+}
+
+// WithoutColor will retrieve a variant of the style with all colors
+// and other text attributes removed. Styles that draw the bar using
+// background colors only, such as MinimalStyle(), would be invisible
+// without them, so they are drawn using block characters instead.
+func (s Style) WithoutColor() Style {
+    background := s.TrackColor != ""
+
+    s = s.mapSequences(func(value string) string {
+        return sgr_re.ReplaceAllString(value, "")
+    })
+
+    if background {
+        if strings.TrimSpace(s.DoneChar) == "" {
+            s.DoneChar = "█"
+        }
+        if strings.TrimSpace(s.NotDoneChar) == "" {
+            s.NotDoneChar = "░"
+        }
+    }
+
+    return s
+}
+
+// colorless will determine whether the progress bar should be drawn
+// without colors. The caller must hold pb.mu.
+func (pb *ProgressBar) colorless() bool {
+    return pb.colors != nil && !pb.colors.ColorEnabled()
+}
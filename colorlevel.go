@@ -0,0 +1,220 @@
+package progresscli
+
+import (
+    "os"
+    "strconv"
+    "strings"
+)
+
+// ColorLevel is the range of colors a terminal is able to display.
+type ColorLevel int
+
+const (
+    // NoColor is the level of terminals that can't display colors.
+    NoColor ColorLevel = iota
+
+    // BasicColor is the level of terminals limited to the 8 basic ANSI
+    // colors and their bright variants, such as the Linux console.
+    BasicColor
+
+    // Color256 is the level of terminals supporting the 256 color
+    // palette.
+    Color256
+
+    // TrueColor is the level of terminals supporting 24-bit colors.
+    TrueColor
+)
+
+// basicTerminals lists the values of the TERM environment variable
+// used by terminals limited to the basic colors.
+var basicTerminals = map[string]bool{
+    "linux":  true,
+    "ansi":   true,
+    "vt100":  true,
+    "vt102":  true,
+    "vt220":  true,
+    "cons25": true,
+    "cygwin": true,
+}
+
+// basicPalette holds the colors of the 16 basic ANSI colors, as used
+// by xterm, to find the one closest to a 256 or 24-bit color.
+var basicPalette = [16][3]int{
+    {0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+    {0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+    {127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+    {92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+// cubeLevels holds the intensities of the 6x6x6 color cube of the 256
+// color palette.
+var cubeLevels = [6]int{0, 95, 135, 175, 215, 255}
+
+// DetectColorLevel will guess the range of colors the terminal is able
+// to display from the TERM and COLORTERM environment variables. Only
+// terminals known to be limited, such as the Linux console, are
+// reported as such. Other terminals are assumed to support all colors,
+// as most terminal emulators do.
+func DetectColorLevel() ColorLevel {
+    term := os.Getenv("TERM")
+    colorterm := strings.ToLower(os.Getenv("COLORTERM"))
+
+    switch {
+    case term == "dumb":
+        return NoColor
+    case colorterm == "truecolor" || colorterm == "24bit":
+        return TrueColor
+    case strings.Contains(term, "256color"):
+        return Color256
+    case basicTerminals[term]:
+        return BasicColor
+    }
+
+    return TrueColor
+}
+
+// SetColorLevel will set the range of colors the terminal is able to
+// display. Colors of the progress bar's style outside of that range
+// are replaced with the closest color within it when rendering, see
+// ForColorLevel(). The level defaults to the one reported by
+// DetectColorLevel() when the progress bar is created.
+func (pb *ProgressBar) SetColorLevel(level ColorLevel) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    pb.colorLevel = level
+    pb.restyle()
+    pb.update()
+}
+
+// ForColorLevel will retrieve a variant of the style that only uses
+// colors the specified level supports. 24-bit colors are replaced with
+// the closest color of the 256 color palette, and both are replaced
+// with the closest basic color on terminals limited to those. For
+// NoColor, the style is retrieved without colors, see WithoutColor().
+func (s Style) ForColorLevel(level ColorLevel) Style {
+    if level == NoColor {
+        return s.WithoutColor()
+    }
+
+    if level >= TrueColor {
+        return s
+    }
+
+    return s.mapSequences(func(value string) string {
+        return sgr_re.ReplaceAllStringFunc(value, func(seq string) string {
+            return downgradeSGR(seq, level)
+        })
+    })
+}
+
+// downgradeSGR will replace the 256 and 24-bit colors of an SGR escape
+// sequence with colors supported by the specified level.
+func downgradeSGR(seq string, level ColorLevel) string {
+    params := strings.Split(seq[2:len(seq)-1], ";")
+
+    var out []string
+    for i := 0; i < len(params); i++ {
+        p := params[i]
+        if (p != "38" && p != "48") || i+1 >= len(params) {
+            out = append(out, p)
+            continue
+        }
+
+        base := 30
+        if p == "48" {
+            base = 40
+        }
+
+        switch {
+        case params[i+1] == "5" && i+2 < len(params):
+            n, _ := strconv.Atoi(params[i+2])
+            i += 2
+            if level >= Color256 {
+                out = append(out, p, "5", strconv.Itoa(n))
+            } else {
+                out = append(out, basicColor(base, paletteIndex(n)))
+            }
+        case params[i+1] == "2" && i+4 < len(params):
+            r, _ := strconv.Atoi(params[i+2])
+            g, _ := strconv.Atoi(params[i+3])
+            b, _ := strconv.Atoi(params[i+4])
+            i += 4
+            if level >= Color256 {
+                out = append(out, p, "5", strconv.Itoa(cubeIndex(r, g, b)))
+            } else {
+                out = append(out, basicColor(base, nearestBasic(r, g, b)))
+            }
+        default:
+            out = append(out, p)
+        }
+    }
+
+    return "\033[" + strings.Join(out, ";") + "m"
+}
+
+// basicColor will build the SGR parameter selecting the basic color
+// with the specified index, where base is 30 for the foreground and 40
+// for the background.
+func basicColor(base, index int) string {
+    if index >= 8 {
+        return strconv.Itoa(base + 60 + index - 8)
+    }
+
+    return strconv.Itoa(base + index)
+}
+
+// paletteIndex will find the basic color closest to the color with
+// the specified index in the 256 color palette.
+func paletteIndex(n int) int {
+    switch {
+    case n < 0 || n > 255:
+        return 7
+    case n < 16:
+        return n
+    case n < 232:
+        n -= 16
+        return nearestBasic(cubeLevels[n/36], cubeLevels[n/6%6], cubeLevels[n%6])
+    }
+
+    gray := 8 + (n-232)*10
+    return nearestBasic(gray, gray, gray)
+}
+
+// nearestBasic will find the basic color closest to a 24-bit color.
+func nearestBasic(r, g, b int) int {
+    best, distance := 0, -1
+    for i, c := range basicPalette {
+        dr, dg, db := r-c[0], g-c[1], b-c[2]
+        if d := dr*dr + dg*dg + db*db; distance < 0 || d < distance {
+            best, distance = i, d
+        }
+    }
+
+    return best
+}
+
+// cubeIndex will find the color of the 256 color palette's color cube
+// closest to a 24-bit color.
+func cubeIndex(r, g, b int) int {
+    level := func(v int) int {
+        best := 0
+        for i, l := range cubeLevels {
+            if abs(v-l) < abs(v-cubeLevels[best]) {
+                best = i
+            }
+        }
+        return best
+    }
+
+    return 16 + 36*level(r) + 6*level(g) + level(b)
+}
+
+// abs will compute the absolute value of an integer.
+func abs(n int) int {
+    if n < 0 {
+        return -n
+    }
+
+    return n
+}
@@ -0,0 +1,102 @@
+package progresscli
+
+import (
+    "os"
+    "os/exec"
+    "strings"
+)
+
+// SetFinishCommand will set an external command that is run once the
+// progress bar finishes, for example to play a sound or display a
+// desktop notification. The command line is split into arguments
+// following the quoting rules of a POSIX shell, but it is not run by a
+// shell, so pipes, redirections and variables are not supported. An
+// empty command line removes the command.
+//
+// The command is started in the background, so it never delays the
+// rendering of the progress bar, and its output is discarded. The
+// label and phase of the progress bar are available to the command in
+// the PROGRESSCLI_LABEL and PROGRESSCLI_PHASE environment variables.
+func (pb *ProgressBar) SetFinishCommand(command string) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    pb.finishCommand = splitArgs(command)
+}
+
+// SetFailureCommand will set an external command that is run if the
+// progress bar is aborted. See SetFinishCommand() for how the command
+// is run.
+func (pb *ProgressBar) SetFailureCommand(command string) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    pb.failureCommand = splitArgs(command)
+}
+
+// runCommand will start the finish or failure command of the progress
+// bar, depending on whether it was aborted. The caller must hold pb.mu.
+func (pb *ProgressBar) runCommand() {
+    args := pb.finishCommand
+    if pb.aborted {
+        args = pb.failureCommand
+    }
+
+    if len(args) == 0 {
+        return
+    }
+
+    cmd := exec.Command(args[0], args[1:]...)
+    cmd.Env = append(os.Environ(),
+        "PROGRESSCLI_LABEL="+pb.label,
+        "PROGRESSCLI_PHASE="+pb.phase().String())
+
+    go cmd.Run()
+}
+
+// splitArgs will split a command line into arguments. Arguments are
+// separated by whitespace, which can be included in an argument by
+// quoting it with single or double quotes or escaping it with a
+// backslash.
+func splitArgs(command string) []string {
+    var args    []string
+    var current strings.Builder
+    var quote   rune
+    var escaped bool
+    var inArg   bool
+
+    for _, c := range command {
+        switch {
+        case escaped:
+            current.WriteRune(c)
+            escaped = false
+        case c == '\\' && quote != '\'':
+            escaped = true
+            inArg = true
+        case quote != 0:
+            if c == quote {
+                quote = 0
+            } else {
+                current.WriteRune(c)
+            }
+        case c == '\'' || c == '"':
+            quote = c
+            inArg = true
+        case c == ' ' || c == '\t' || c == '\n':
+            if inArg {
+                args = append(args, current.String())
+                current.Reset()
+                inArg = false
+            }
+        default:
+            current.WriteRune(c)
+            inArg = true
+        }
+    }
+
+    if inArg {
+        args = append(args, current.String())
+    }
+
+    return args
+}
@@ -0,0 +1,14 @@
+//go:build !progresscli_minimal
+
+package progresscli
+
+import (
+    "github.com/nathan-fiscaletti/consolesize-go"
+)
+
+// consoleSize will retrieve the number of columns and rows of the
+// terminal using the consolesize package. Build with the
+// progresscli_minimal tag to drop the dependency.
+func consoleSize() (int, int) {
+    return consolesize.GetConsoleSize()
+}
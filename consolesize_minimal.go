@@ -0,0 +1,29 @@
+//go:build progresscli_minimal
+
+package progresscli
+
+import (
+    "os"
+    "strconv"
+)
+
+// consoleSize will retrieve the number of columns and rows of the
+// terminal from the COLUMNS and LINES environment variables, falling
+// back to 80 columns and 24 rows. It is used instead of the
+// consolesize package when building with the progresscli_minimal tag,
+// so that the package has no dependencies outside the standard
+// library.
+func consoleSize() (int, int) {
+    return envSize("COLUMNS", 80), envSize("LINES", 24)
+}
+
+// envSize will parse a positive size from the specified environment
+// variable, or return fallback if it isn't set to one.
+func envSize(name string, fallback int) int {
+    size, err := strconv.Atoi(os.Getenv(name))
+    if err != nil || size <= 0 {
+        return fallback
+    }
+
+    return size
+}
@@ -0,0 +1,62 @@
+package progresscli
+
+import (
+    "time"
+)
+
+// counterInterval is how often a counter is redrawn to animate its
+// spinner and keep its elapsed time moving.
+const counterInterval = 100 * time.Millisecond
+
+// counterSpinner is the spinner of a counter whose style has none.
+var counterSpinner = []string{
+    "⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏",
+}
+
+// NewByteCounter will create a new progress bar counting the bytes of
+// a stream whose total size is unknown, such as a download without a
+// Content-Length. Instead of a bar, it displays a spinner followed by
+// the number of bytes counted so far, the current rate and the time
+// elapsed, e.g. "⠹ 182.0 MiB  23.1 MiB/s  0:01:12". The value is
+// increased as usual using Increment(). Like other indeterminate
+// progress bars, see SetZeroMax(), a counter doesn't finish on its
+// own; set its max value to the final count using SetMax() once the
+// stream ends, or call Abort().
+func NewByteCounter(label string) *ProgressBar {
+    pb := New()
+    pb.counter = true
+    pb.max = 0
+    pb.zeroMax = ZeroMaxIndeterminate
+    pb.label = label
+    pb.showLabel = strLen(label) > 0
+    pb.showPercentage = false
+    return pb
+}
+
+// counterText will build the text displayed in place of the bar by a
+// counter: a spinner, which is replaced by the done character of the
+// style once the counter completes, the number of bytes, the rate and
+// the elapsed time.
+func counterText(s State) string {
+    spinner := s.Style.Spinner
+    if len(spinner) == 0 {
+        spinner = counterSpinner
+    }
+
+    mark := spinner[s.Frame%len(spinner)]
+    if s.Complete {
+        mark = s.Style.DoneChar
+    }
+
+    // The rate is measured over the recent history, which is empty
+    // until the stream has made progress twice, so fall back to the
+    // average rate.
+    rate := s.Rate
+    if elapsed := s.Elapsed().Seconds(); rate <= 0 && elapsed > 0 {
+        rate = s.Value / elapsed
+    }
+
+    return mark + " " + formatBytes(s.Value) +
+        "  " + formatBytes(rate) + "/s" +
+        "  " + formatDuration(s.Elapsed())
+}
@@ -0,0 +1,46 @@
+package progresscli
+
+import (
+    "math"
+)
+
+// SetDecimalsAbove will tell the progress bar to display the whole
+// percentage until it reaches the specified threshold, and the
+// percentage with two decimals from then on until it is complete, so
+// that very long jobs visibly keep moving instead of sitting at 99%
+// for minutes. When counting down, decimals are displayed once the
+// remaining percentage falls below 100 minus the threshold. Zero, the
+// default, never adds decimals. It has no effect if the percentage is
+// always displayed with decimals, see SetShowPercentageDecimal().
+func (pb *ProgressBar) SetDecimalsAbove(threshold float64) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    pb.decimalsAbove = threshold
+    pb.update()
+}
+
+// adaptDecimals will switch the state to display the percentage with
+// two decimals if it is in the final stretch set using
+// SetDecimalsAbove(). The caller must hold pb.mu.
+func (pb *ProgressBar) adaptDecimals(s *State) {
+    if pb.decimalsAbove <= 0 || s.ShowPercentageDecimal ||
+       s.Indeterminate || s.Complete || s.DryRun || pb.max == 0 {
+        return
+    }
+
+    exact := s.Value / pb.max * 100
+    done := exact
+    if s.Direction == Down {
+        done = 100 - exact
+    }
+
+    if done < pb.decimalsAbove {
+        return
+    }
+
+    // Truncate rather than round, so that an unfinished progress bar
+    // never displays 100.00%.
+    s.Percent = math.Trunc(exact*100) / 100
+    s.ShowPercentageDecimal = true
+}
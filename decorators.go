@@ -0,0 +1,83 @@
+package progresscli
+
+import (
+    "fmt"
+    "time"
+)
+
+// ETADecorator will create a decorator that displays the estimated
+// time remaining until the progress bar is complete, based on the
+// average rate of progress since the bar was shown.
+func ETADecorator() Decorator {
+    return func(s State) string {
+        eta, ok := s.ETA()
+        if !ok {
+            return "ETA --:--"
+        }
+
+        return "ETA " + formatDuration(eta)
+    }
+}
+
+// ElapsedDecorator will create a decorator that displays the time
+// that has passed since the progress bar was shown.
+func ElapsedDecorator() Decorator {
+    return func(s State) string {
+        return formatDuration(s.Elapsed())
+    }
+}
+
+// BytesDecorator will create a decorator that displays the value and
+// max value of the progress bar as byte counts, for example
+// "1.5 MiB/10.0 MiB".
+func BytesDecorator() Decorator {
+    return func(s State) string {
+        return formatBytes(s.Value) + "/" + formatBytes(s.Max)
+    }
+}
+
+// CountsDecorator will create a decorator that displays the number of
+// completed, skipped and failed items, for example
+// "120 ok, 30 skipped, 3 failed". The skipped and failed counts are
+// only displayed once an item has been skipped or has failed.
+func CountsDecorator() Decorator {
+    return func(s State) string {
+        text := fmt.Sprintf("%.0f ok", s.done()-s.Skipped-s.Failed)
+        if s.Skipped > 0 {
+            text += fmt.Sprintf(", %.0f skipped", s.Skipped)
+        }
+        if s.Failed > 0 {
+            text += fmt.Sprintf(", %.0f failed", s.Failed)
+        }
+
+        return text
+    }
+}
+
+// formatDuration will format a duration as h:mm:ss.
+func formatDuration(d time.Duration) string {
+    d = d.Round(time.Second)
+    h := d / time.Hour
+    d -= h * time.Hour
+    m := d / time.Minute
+    d -= m * time.Minute
+    s := d / time.Second
+
+    return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+}
+
+// formatBytes will format a byte count using binary (IEC) units.
+func formatBytes(b float64) string {
+    const unit = 1024.0
+    if b < unit {
+        return fmt.Sprintf("%.0f B", b)
+    }
+
+    units := "KMGTPE"
+    i := 0
+    for b /= unit; b >= unit && i < len(units)-1; i++ {
+        b /= unit
+    }
+
+    return fmt.Sprintf("%.1f %ciB", b, units[i])
+}
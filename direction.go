@@ -0,0 +1,67 @@
+package progresscli
+
+// Direction is the direction in which the value of a progress bar
+// moves as work is done.
+type Direction int
+
+const (
+    // Up is the default direction. The value of the progress bar
+    // counts up from zero and the progress bar finishes once the
+    // value reaches its max value.
+    Up Direction = iota
+
+    // Down makes the value of the progress bar count down from its
+    // max value, for example the number of items remaining in a
+    // queue. The fill shrinks as the value decreases and the progress
+    // bar finishes once the value reaches zero.
+    Down
+)
+
+// SetDirection will set the direction in which the value of the
+// progress bar moves. When the direction changes, the value is
+// mirrored so that the amount of work done is kept, meaning a progress
+// bar that hasn't made any progress yet starts at its max value when
+// counting down.
+func (pb *ProgressBar) SetDirection(d Direction) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    if d == pb.direction {
+        return
+    }
+
+    pb.direction = d
+    pb.value = pb.max - pb.value
+    pb.update()
+}
+
+// GetDirection will retrieve the direction in which the value of the
+// progress bar moves.
+func (pb *ProgressBar) GetDirection() Direction {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    return pb.direction
+}
+
+// complete will determine whether the value of the progress bar has
+// reached its end. The caller must hold pb.mu.
+func (pb *ProgressBar) complete() bool {
+    if pb.endless() {
+        return pb.stopped
+    }
+
+    if pb.dryRun {
+        return false
+    }
+
+    if pb.indeterminate() {
+        return false
+    }
+
+    if pb.direction == Down {
+        return pb.value <= 0
+    }
+
+    return pb.percent() >= 100
+}
@@ -0,0 +1,38 @@
+package progresscli
+
+// SetDryRun will switch the progress bar into or out of dry run mode,
+// for --dry-run flows that only plan work. In dry run mode the bar is
+// drawn completely unfilled, and instead of the text of its decorators
+// the amount of planned work, its max value, is displayed, e.g.
+// "120 planned". Time based decorators such as the ETA are not run,
+// and the progress bar doesn't finish.
+//
+// Switching out of dry run mode while the progress bar is visible
+// starts the real run on the same bar, in the same place: the clock
+// used by time based decorators is restarted and the bar is drawn
+// normally. The value of the progress bar is not changed.
+func (pb *ProgressBar) SetDryRun(dryRun bool) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    if pb.dryRun && !dryRun && pb.visible {
+        pb.start()
+    }
+
+    pb.dryRun = dryRun
+    pb.update()
+}
+
+// DryRun will determine whether the progress bar is in dry run mode.
+func (pb *ProgressBar) DryRun() bool {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    return pb.dryRun
+}
+
+// plannedDecoration will build the text displayed in place of the
+// decorations in dry run mode.
+func plannedDecoration(s State) string {
+    return formatNumber(s.Max) + " planned"
+}
@@ -0,0 +1,75 @@
+package progresscli
+
+import (
+    "math"
+    "time"
+)
+
+const (
+    // easeFrames is the number of frames an eased jump is drawn in if
+    // the progress bar has no refresh interval.
+    easeFrames = 10
+
+    // easeThreshold is the smallest jump, as a fraction of the max
+    // value, that is eased. Smaller changes are drawn right away.
+    easeThreshold = 0.02
+)
+
+// SetEasing will animate large jumps in the value of the progress bar
+// over the specified duration instead of drawing them right away. The
+// animation is purely visual: the value, decorators and the moment the
+// progress bar finishes are not affected. The animation is drawn on
+// every tick of the refresh interval, or in a few frames if there is
+// none. A duration of zero disables easing, which is the default.
+func (pb *ProgressBar) SetEasing(d time.Duration) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    pb.stopTicker()
+    pb.easing = d
+    pb.easeTo = pb.value
+    pb.easeStart = time.Time{}
+    if pb.visible && !pb.finished {
+        pb.startTicker()
+    }
+}
+
+// retarget will start animating towards the current value of the
+// progress bar if it jumped. The caller must hold pb.mu.
+func (pb *ProgressBar) retarget() {
+    if pb.easing <= 0 || pb.value == pb.easeTo {
+        return
+    }
+
+    current := pb.eased()
+    if !pb.animating() &&
+       math.Abs(pb.value-current) < pb.max*easeThreshold {
+        pb.easeTo = pb.value
+        pb.easeStart = time.Time{}
+        return
+    }
+
+    pb.easeFrom = current
+    pb.easeTo = pb.value
+    pb.easeStart = pb.now()
+}
+
+// animating will determine whether an eased jump is being drawn. The
+// caller must hold pb.mu.
+func (pb *ProgressBar) animating() bool {
+    return pb.easing > 0 && !pb.easeStart.IsZero() &&
+           pb.since(pb.easeStart) < pb.easing
+}
+
+// eased will compute the value that should be drawn, following an
+// ease-out curve from the value before a jump to the value after it.
+// The caller must hold pb.mu.
+func (pb *ProgressBar) eased() float64 {
+    if !pb.animating() {
+        return pb.easeTo
+    }
+
+    t := float64(pb.since(pb.easeStart)) / float64(pb.easing)
+    t = 1 - math.Pow(1-t, 3)
+    return pb.easeFrom + (pb.easeTo-pb.easeFrom)*t
+}
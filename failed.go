@@ -0,0 +1,44 @@
+package progresscli
+
+// IncrementFailed will advance the progress bar by the specified count
+// while recording the items as failed rather than completed. Failed
+// items still count towards the progress of the bar, since they have
+// been processed. Use CountsDecorator() to display the number of
+// completed and failed items, and the FailedChar of the style to show
+// them as a separate segment of the fill.
+func (pb *ProgressBar) IncrementFailed(count float64) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    if pb.finished || !pb.visible {
+        return
+    }
+
+    pb.failed += count
+    if pb.direction == Down {
+        pb.value -= count
+    } else {
+        pb.value += count
+    }
+
+    pb.update()
+}
+
+// Failed will retrieve the number of items recorded as failed using
+// IncrementFailed().
+func (pb *ProgressBar) Failed() float64 {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    return pb.failed
+}
+
+// done will compute the amount of work processed so far, regardless
+// of the direction of the progress bar.
+func (s State) done() float64 {
+    if s.Direction == Down {
+        return s.Max - s.Value
+    }
+
+    return s.Value
+}
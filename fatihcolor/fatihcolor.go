@@ -0,0 +1,30 @@
+// Package fatihcolor adapts the global color setting of
+// github.com/fatih/color for use with progresscli.
+package fatihcolor
+
+import (
+    "github.com/fatih/color"
+    "github.com/nathan-fiscaletti/progresscli-go"
+)
+
+// Provider is a progresscli.ColorProvider that follows color.NoColor,
+// which fatih/color sets when output is not a terminal or NO_COLOR is
+// set, and which programs can override to disable colors globally.
+type Provider struct{}
+
+// New will create a new Provider.
+func New() Provider {
+    return Provider{}
+}
+
+// ColorEnabled will determine whether colors are enabled according to
+// fatih/color.
+func (Provider) ColorEnabled() bool {
+    return !color.NoColor
+}
+
+// Apply will make the progress bar follow the color setting of
+// fatih/color.
+func Apply(pb *progresscli.ProgressBar) {
+    pb.SetColorProvider(New())
+}
@@ -0,0 +1,110 @@
+package progresscli
+
+import (
+    "bufio"
+    "bytes"
+    "io"
+    "strconv"
+    "strings"
+    "sync"
+    "time"
+)
+
+// FFmpegAdapter parses the key/value output written by ffmpeg when run
+// with `-progress pipe:1` (or any other -progress target) and turns it
+// into progress bar updates. Since ffmpeg does not report the total
+// duration of its output, it must be supplied by the caller.
+//
+// The adapter adds a decorator to the progress bar that displays the
+// current transcoding speed as reported by ffmpeg (e.g. "1.7x").
+//
+// A FFmpegAdapter implements io.Writer, so it can be assigned directly
+// to the Stdout field of an exec.Cmd.
+type FFmpegAdapter struct {
+    mu    sync.Mutex
+    bar   *ProgressBar
+    speed string
+    buf   []byte
+}
+
+// NewFFmpegAdapter will create a new FFmpegAdapter that drives the
+// specified progress bar. The max value of the progress bar is set
+// to the number of seconds in duration.
+func NewFFmpegAdapter(pb *ProgressBar, duration time.Duration) *FFmpegAdapter {
+    a := &FFmpegAdapter{
+        bar: pb,
+    }
+
+    pb.SetMax(duration.Seconds())
+    pb.AddDecorator(a.speedDecorator)
+    return a
+}
+
+// Speed will retrieve the most recent transcoding speed reported by
+// ffmpeg, for example "1.7x". An empty string is returned if no speed
+// has been reported yet.
+func (a *FFmpegAdapter) Speed() string {
+    a.mu.Lock()
+    defer a.mu.Unlock()
+
+    return a.speed
+}
+
+// Write implements io.Writer. Partial lines are buffered until the
+// rest of the line has been written.
+func (a *FFmpegAdapter) Write(p []byte) (int, error) {
+    a.buf = append(a.buf, p...)
+    for {
+        i := bytes.IndexByte(a.buf, '\n')
+        if i < 0 {
+            break
+        }
+
+        a.handleLine(string(a.buf[:i]))
+        a.buf = a.buf[i+1:]
+    }
+
+    return len(p), nil
+}
+
+// Watch will read ffmpeg's progress output from r until EOF, updating
+// the progress bar as progress blocks are received.
+func (a *FFmpegAdapter) Watch(r io.Reader) error {
+    scanner := bufio.NewScanner(r)
+    for scanner.Scan() {
+        a.handleLine(scanner.Text())
+    }
+
+    return scanner.Err()
+}
+
+func (a *FFmpegAdapter) handleLine(line string) {
+    key, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+    if !ok {
+        return
+    }
+
+    switch key {
+    case "out_time_us":
+        if us, err := strconv.ParseInt(value, 10, 64); err == nil {
+            a.bar.SetValue(
+                (time.Duration(us) * time.Microsecond).Seconds())
+        }
+    case "speed":
+        if value == "N/A" {
+            value = ""
+        }
+
+        a.mu.Lock()
+        a.speed = strings.TrimSpace(value)
+        a.mu.Unlock()
+    case "progress":
+        if value == "end" {
+            a.bar.SetValue(a.bar.GetMax())
+        }
+    }
+}
+
+func (a *FFmpegAdapter) speedDecorator(s State) string {
+    return a.Speed()
+}
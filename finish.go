@@ -0,0 +1,155 @@
+package progresscli
+
+import (
+    "fmt"
+)
+
+// FinishAll will complete every progress bar of the manager that is
+// still running, then stop the manager and write a summary line below
+// its rows. See Summary() for its format.
+func (m *Manager) FinishAll() {
+    for _, pb := range m.Bars() {
+        pb.mu.Lock()
+        pb.finish()
+        pb.mu.Unlock()
+    }
+
+    m.stopWithSummary()
+}
+
+// AbortAll will abort every progress bar of the manager that is still
+// running, then stop the manager and write a summary line below its
+// rows. See Summary() for its format.
+func (m *Manager) AbortAll() {
+    for _, pb := range m.Bars() {
+        pb.Abort()
+    }
+
+    m.stopWithSummary()
+}
+
+// Wait will block until every progress bar of the manager has finished
+// or been aborted, then stop the manager and write a summary line
+// below its rows. See Summary() for its format. Wait should only be
+// called once the manager is shown, since the progress bars of a
+// hidden manager never finish.
+func (m *Manager) Wait() {
+    m.mu.Lock()
+    for !m.allEnded() {
+        m.cond.Wait()
+    }
+    m.mu.Unlock()
+
+    m.stopWithSummary()
+}
+
+// Summary will describe the outcome of the manager's progress bars,
+// for example "3 finished, 1 aborted, 2 failed". The numbers of failed
+// and skipped items recorded by the progress bars are only included if
+// there are any.
+func (m *Manager) Summary() string {
+    var finished, aborted, running int
+    var failed, skipped float64
+
+    for _, pb := range m.Bars() {
+        pb.mu.Lock()
+        switch pb.phase() {
+        case Finished:
+            finished++
+        case Aborted:
+            aborted++
+        default:
+            running++
+        }
+        failed += pb.failed
+        skipped += pb.skipped
+        pb.mu.Unlock()
+    }
+
+    summary := fmt.Sprintf("%d finished", finished)
+    if aborted > 0 {
+        summary += fmt.Sprintf(", %d aborted", aborted)
+    }
+    if running > 0 {
+        summary += fmt.Sprintf(", %d unfinished", running)
+    }
+    if skipped > 0 {
+        summary += fmt.Sprintf(", %.0f skipped", skipped)
+    }
+    if failed > 0 {
+        summary += fmt.Sprintf(", %.0f failed", failed)
+    }
+
+    return summary
+}
+
+// stopWithSummary will stop the manager and write its summary below
+// its rows.
+func (m *Manager) stopWithSummary() {
+    m.mu.Lock()
+    visible := m.visible
+    m.mu.Unlock()
+
+    m.Stop()
+    if !visible {
+        return
+    }
+
+    summary := m.Summary()
+
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    fmt.Fprintln(m.writer, summary)
+    if m.autoFlush {
+        flush(m.writer)
+    }
+}
+
+// ended will record whether a progress bar of the manager has finished
+// or been aborted and wake up callers of Wait(). It is called by
+// progress bars holding their own lock. The caller must hold m.mu.
+func (m *Manager) ended(pb *ProgressBar, ended bool) {
+    if _, ok := m.ends[pb]; !ok {
+        return
+    }
+
+    m.ends[pb] = ended
+    m.cond.Broadcast()
+    m.checkIdle()
+}
+
+// allEnded will determine whether all progress bars of the manager
+// have finished or been aborted. The caller must hold m.mu.
+func (m *Manager) allEnded() bool {
+    for _, ended := range m.ends {
+        if !ended {
+            return false
+        }
+    }
+
+    return true
+}
+
+// finish will complete the progress bar by moving its value to its
+// end and drawing it, unless it is not running. The caller must hold
+// pb.mu.
+func (pb *ProgressBar) finish() {
+    if !pb.visible || pb.finished {
+        return
+    }
+
+    switch {
+    case pb.endless():
+        pb.stopped = true
+    case pb.direction == Down:
+        pb.value = 0
+    default:
+        pb.value = pb.max
+    }
+
+    // An indeterminate progress bar can't complete on its own.
+    pb.zeroMax = ZeroMaxComplete
+    pb.paused = false
+    pb.update()
+}
@@ -0,0 +1,41 @@
+package progresscli
+
+import (
+    "io"
+)
+
+// SetAutoFlush will set whether the progress bar flushes its writer
+// after each frame. If enabled and the writer has a Flush() method,
+// such as a bufio.Writer or an http.Flusher, or otherwise a Sync()
+// method, such as an os.File, it is called after every frame, so that
+// the progress bar appears in real time instead of once the writer's
+// buffer fills up. Auto-flushing is disabled by default, since syncing
+// a file on every frame can be slow.
+func (pb *ProgressBar) SetAutoFlush(enabled bool) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    pb.autoFlush = enabled
+}
+
+// SetAutoFlush will set whether the manager flushes its writer after
+// each update. See ProgressBar.SetAutoFlush() for details.
+func (m *Manager) SetAutoFlush(enabled bool) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    m.autoFlush = enabled
+}
+
+// flush will flush or sync w, if it supports either. Errors are
+// ignored, since terminals commonly fail to sync.
+func flush(w io.Writer) {
+    switch f := w.(type) {
+    case interface{ Flush() error }:
+        f.Flush()
+    case interface{ Flush() }:
+        f.Flush()
+    case interface{ Sync() error }:
+        f.Sync()
+    }
+}
@@ -0,0 +1,191 @@
+package progresscli
+
+import (
+    "bufio"
+    "bytes"
+    "io"
+    "net/url"
+    "os"
+    "strconv"
+    "strings"
+    "sync"
+    "sync/atomic"
+)
+
+// ForwardEnv is the environment variable telling a child process using
+// this package to forward its progress bars to its parent. Progress
+// bars created while it is set to a non-empty value use the Forward
+// line mode.
+const ForwardEnv = "PROGRESSCLI_FORWARD"
+
+// forwardPrefix starts every line of the forwarding protocol. It is an
+// OSC sequence, which terminals ignore if the line reaches them
+// without being intercepted.
+const forwardPrefix = "\033]progress;"
+
+// forwardIDs is used to assign each forwarded progress bar of a
+// process a unique ID.
+var forwardIDs int64
+
+// forwarding will determine whether progress bars of this process
+// should be forwarded to its parent.
+func forwarding() bool {
+    return os.Getenv(ForwardEnv) != ""
+}
+
+// forwardLine will encode the state of the progress bar as a line of
+// the forwarding protocol. The caller must hold pb.mu.
+func (pb *ProgressBar) forwardLine() string {
+    if pb.forwardID == 0 {
+        pb.forwardID = atomic.AddInt64(&forwardIDs, 1)
+    }
+
+    values := url.Values{}
+    values.Set("id", strconv.FormatInt(pb.forwardID, 10))
+    values.Set("value", strconv.FormatFloat(pb.value, 'f', -1, 64))
+    values.Set("max", strconv.FormatFloat(pb.max, 'f', -1, 64))
+    values.Set("phase", pb.phase().String())
+    if pb.label != "" {
+        values.Set("label", pb.label)
+    }
+    if pb.verb != "" {
+        values.Set("verb", pb.verb)
+    }
+    if pb.direction == Down {
+        values.Set("direction", "down")
+    }
+    if pb.zeroMax == ZeroMaxIndeterminate {
+        values.Set("zeromax", "indeterminate")
+    }
+
+    return forwardPrefix + values.Encode() + "\007"
+}
+
+// ForwardAdapter intercepts the output of a child process whose
+// progress bars are forwarded using the Forward line mode, and renders
+// them as rows of a Manager in the parent process. All other output
+// of the child is passed through. Set ForwardEnv in the environment of
+// the child to enable forwarding, for example:
+//
+//     cmd.Env = append(os.Environ(), progresscli.ForwardEnv+"=1")
+//     cmd.Stdout = progresscli.NewForwardAdapter(manager, os.Stdout)
+//
+// A ForwardAdapter implements io.Writer and is safe for concurrent
+// use.
+type ForwardAdapter struct {
+    mu      sync.Mutex
+    manager *Manager
+    out     io.Writer
+    bars    map[string]*ProgressBar
+    buf     []byte
+}
+
+// NewForwardAdapter will create a new ForwardAdapter that adds the
+// forwarded progress bars to the specified manager and writes all
+// other output to out, above the rows of the manager, see
+// Manager.Println(). If out is nil, other output is discarded.
+func NewForwardAdapter(m *Manager, out io.Writer) *ForwardAdapter {
+    if out == nil {
+        out = io.Discard
+    }
+
+    return &ForwardAdapter{
+        manager: m,
+        out: out,
+        bars: map[string]*ProgressBar{},
+    }
+}
+
+// Write implements io.Writer. Partial lines are buffered until the
+// rest of the line has been written.
+func (a *ForwardAdapter) Write(p []byte) (int, error) {
+    a.mu.Lock()
+    defer a.mu.Unlock()
+
+    a.buf = append(a.buf, p...)
+    for {
+        i := bytes.IndexByte(a.buf, '\n')
+        if i < 0 {
+            break
+        }
+
+        a.handleLine(string(a.buf[:i]))
+        a.buf = a.buf[i+1:]
+    }
+
+    return len(p), nil
+}
+
+// Watch will read from r until EOF, handling each line.
+func (a *ForwardAdapter) Watch(r io.Reader) error {
+    scanner := bufio.NewScanner(r)
+    for scanner.Scan() {
+        a.mu.Lock()
+        a.handleLine(scanner.Text())
+        a.mu.Unlock()
+    }
+
+    return scanner.Err()
+}
+
+// handleLine will update the progress bar described by a line of the
+// forwarding protocol, or pass any other line through above the rows
+// of the manager. The caller must hold a.mu.
+func (a *ForwardAdapter) handleLine(line string) {
+    line = strings.TrimSuffix(line, "\r")
+    if !strings.HasPrefix(line, forwardPrefix) ||
+       !strings.HasSuffix(line, "\007") {
+        a.manager.mu.Lock()
+        a.manager.print(a.out, line+"\n")
+        a.manager.mu.Unlock()
+        return
+    }
+
+    line = strings.TrimSuffix(strings.TrimPrefix(line, forwardPrefix), "\007")
+    values, err := url.ParseQuery(line)
+    if err != nil {
+        return
+    }
+
+    id := values.Get("id")
+    pb, ok := a.bars[id]
+    if !ok {
+        pb = New()
+        a.bars[id] = pb
+        a.manager.Add(pb)
+    }
+
+    pb.SetLabel(values.Get("label"))
+    pb.SetVerb(values.Get("verb"))
+    if values.Get("direction") == "down" {
+        pb.SetDirection(Down)
+    }
+
+    // The zero max behavior is set before the max value, so that an
+    // indeterminate progress bar doesn't complete when its max value
+    // of zero is set.
+    if values.Get("zeromax") == "indeterminate" {
+        pb.SetZeroMax(ZeroMaxIndeterminate)
+    } else {
+        pb.SetZeroMax(ZeroMaxComplete)
+    }
+    if max, err := strconv.ParseFloat(values.Get("max"), 64); err == nil {
+        pb.SetMax(max)
+    }
+    if value, err := strconv.ParseFloat(values.Get("value"), 64); err == nil {
+        pb.SetValue(value)
+    }
+
+    switch values.Get("phase") {
+    case Running.String():
+        pb.Resume()
+    case Paused.String():
+        pb.Pause()
+    case Finished.String():
+        pb.mu.Lock()
+        pb.finish()
+        pb.mu.Unlock()
+    case Aborted.String():
+        pb.Abort()
+    }
+}
@@ -0,0 +1,175 @@
+package progresscli
+
+import (
+    "bufio"
+    "bytes"
+    "io"
+    "regexp"
+    "strconv"
+)
+
+// gitProgressPattern matches the progress lines git writes to stderr,
+// for example "remote: Compressing objects:  50% (5/10)" or
+// "Receiving objects:  42% (420/1000), 1.20 MiB | 2.30 MiB/s".
+var gitProgressPattern = regexp.MustCompile(
+    `^(?:remote: )?([A-Za-z][A-Za-z ]*?):\s+\d+% \((\d+)/(\d+)\)`)
+
+// GitAdapter parses the sideband progress output of git (as printed
+// by clone, fetch, push and friends when run with --progress) and
+// turns it into progress bar updates.
+//
+// Each phase git reports (counting, compressing, receiving, resolving)
+// is rendered as its own bar on its own line. By default a single bar
+// is reused for every phase and labeled with the phase name. You can
+// assign a dedicated bar to a phase using SetPhaseBar().
+//
+// A GitAdapter implements io.Writer, so it can be assigned directly to
+// the Stderr field of an exec.Cmd.
+type GitAdapter struct {
+    bar    *ProgressBar
+    phases map[string]*ProgressBar
+    phase  string
+    buf    []byte
+}
+
+// NewGitAdapter will create a new GitAdapter that renders every phase
+// using the specified progress bar.
+func NewGitAdapter(pb *ProgressBar) *GitAdapter {
+    return &GitAdapter{
+        bar: pb,
+        phases: map[string]*ProgressBar{},
+    }
+}
+
+// SetPhaseBar will assign a dedicated progress bar to the specified
+// phase, for example "Receiving objects". Dedicated bars keep their
+// own label and style.
+func (a *GitAdapter) SetPhaseBar(phase string, pb *ProgressBar) {
+    a.phases[phase] = pb
+}
+
+// Write implements io.Writer. Git separates progress updates using
+// carriage returns, so both '\r' and '\n' are treated as line
+// endings. Partial lines are buffered until they are complete. If the
+// progress bar of a new phase cannot be shown, the error returned by
+// ShowIn() is returned once the rest of p has been handled.
+func (a *GitAdapter) Write(p []byte) (int, error) {
+    var first error
+
+    a.buf = append(a.buf, p...)
+    for {
+        i := bytes.IndexAny(a.buf, "\r\n")
+        if i < 0 {
+            break
+        }
+
+        if err := a.handleLine(string(a.buf[:i])); err != nil && first == nil {
+            first = err
+        }
+        a.buf = a.buf[i+1:]
+    }
+
+    return len(p), first
+}
+
+// Watch will read git's progress output from r until EOF, updating
+// the progress bars for each progress line. If the progress bar of a
+// phase cannot be shown, the error returned by ShowIn() is returned
+// once the output has been read.
+func (a *GitAdapter) Watch(r io.Reader) error {
+    var first error
+
+    scanner := bufio.NewScanner(r)
+    scanner.Split(scanGitLines)
+    for scanner.Scan() {
+        if err := a.handleLine(scanner.Text()); err != nil && first == nil {
+            first = err
+        }
+    }
+
+    if err := scanner.Err(); err != nil {
+        return err
+    }
+
+    return first
+}
+
+func (a *GitAdapter) handleLine(line string) error {
+    m := gitProgressPattern.FindStringSubmatch(line)
+    if m == nil {
+        return nil
+    }
+
+    phase := m[1]
+    current, err := strconv.ParseFloat(m[2], 64)
+    if err != nil {
+        return nil
+    }
+    total, err := strconv.ParseFloat(m[3], 64)
+    if err != nil || total <= 0 {
+        return nil
+    }
+
+    pb := a.barFor(phase)
+    if phase != a.phase {
+        if err := a.startPhase(phase, pb); err != nil {
+            return err
+        }
+    }
+
+    pb.SetMax(total)
+    pb.SetValue(current)
+    return nil
+}
+
+func (a *GitAdapter) barFor(phase string) *ProgressBar {
+    if pb, ok := a.phases[phase]; ok {
+        return pb
+    }
+
+    return a.bar
+}
+
+func (a *GitAdapter) startPhase(phase string, pb *ProgressBar) error {
+    // Terminate the line of a previous phase that never reached 100%
+    // so the next phase does not overwrite it.
+    if a.phase != "" {
+        a.barFor(a.phase).Abort()
+    }
+
+    a.phase = phase
+    if _, ok := a.phases[phase]; !ok {
+        pb.SetLabel(phase)
+    }
+
+    // Progress bars belonging to a Manager are displayed by it.
+    pb.mu.Lock()
+    w := pb.writer
+    managed := pb.manager != nil
+    pb.mu.Unlock()
+
+    if managed {
+        return nil
+    }
+
+    if w == nil {
+        w = DefaultWriter()
+    }
+    return pb.ShowIn(w)
+}
+
+func scanGitLines(data []byte, atEOF bool) (int, []byte, error) {
+    if atEOF && len(data) == 0 {
+        return 0, nil, nil
+    }
+
+    if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+        return i + 1, data[:i], nil
+    }
+
+    if atEOF {
+        return len(data), data, nil
+    }
+
+    return 0, nil, nil
+}
@@ -0,0 +1,188 @@
+package progresscli
+
+import (
+    "sync"
+)
+
+// Group is a set of progress bars of a Manager that share a default
+// style and decorators, so that large applications can keep their
+// progress bars consistent without configuring each of them. Groups
+// can be nested, in which case a group inherits the style of its
+// parent unless it sets one of its own, and displays the decorators of
+// its parents before its own. Progress bars can still override what
+// they inherit, see ProgressBar.SetStyle(), NewWithStyle() and
+// SetGroupDecorators().
+// You should initialize a new group using the Manager.NewGroup() or
+// Group.NewGroup() functions. A Group is safe for concurrent use.
+type Group struct {
+    // mu protects the fields below. A progress bar's own lock is
+    // always acquired before the lock of its group, never after.
+    mu         sync.Mutex
+    manager    *Manager
+    parent     *Group
+    style      *Style
+    decorators []Decorator
+}
+
+// NewGroup will create a new Group adding its progress bars to the
+// manager, without a style or decorators of its own.
+func (m *Manager) NewGroup() *Group {
+    return &Group{manager: m}
+}
+
+// NewGroup will create a new Group nested in the group, inheriting its
+// style and decorators.
+func (g *Group) NewGroup() *Group {
+    return &Group{manager: g.manager, parent: g}
+}
+
+// SetStyle will set the style of the progress bars in the group and
+// in the groups nested in it that don't set a style of their own. The
+// style is applied to the progress bars already added, except those
+// that have set a style using ProgressBar.SetStyle() or were created
+// using NewWithStyle(), and those that have finished, which keep the
+// style they finished with.
+func (g *Group) SetStyle(style Style) {
+    g.mu.Lock()
+    g.style = &style
+    g.mu.Unlock()
+
+    g.refresh()
+}
+
+// AddDecorator will append a decorator to the progress bars in the
+// group and in the groups nested in it. The decorators of a group are
+// displayed before those added to the progress bars themselves, in the
+// order they were added.
+func (g *Group) AddDecorator(d Decorator) {
+    g.mu.Lock()
+    g.decorators = append(g.decorators, d)
+    g.mu.Unlock()
+
+    g.refresh()
+}
+
+// Add will add a progress bar to the group's manager, giving it the
+// style and decorators of the group. See Manager.Add().
+func (g *Group) Add(pb *ProgressBar) {
+    pb.mu.Lock()
+    pb.group = g
+    pb.inherit()
+    pb.mu.Unlock()
+
+    g.manager.Add(pb)
+}
+
+// AddNamed will add a progress bar to the group's manager under the
+// specified name, giving it the style and decorators of the group. See
+// Manager.AddNamed().
+func (g *Group) AddNamed(name string, pb *ProgressBar) error {
+    pb.mu.Lock()
+    previous := pb.group
+    pb.group = g
+    pb.inherit()
+    pb.mu.Unlock()
+
+    err := g.manager.AddNamed(name, pb)
+    if err != nil {
+        pb.mu.Lock()
+        pb.group = previous
+        pb.mu.Unlock()
+    }
+
+    return err
+}
+
+// Bars will retrieve the progress bars of the manager that belong to
+// the group or to a group nested in it, in the order they are
+// displayed.
+func (g *Group) Bars() []*ProgressBar {
+    var bars []*ProgressBar
+    for _, pb := range g.manager.Bars() {
+        pb.mu.Lock()
+        if pb.group.within(g) {
+            bars = append(bars, pb)
+        }
+        pb.mu.Unlock()
+    }
+
+    return bars
+}
+
+// refresh will give the progress bars in the group and in the groups
+// nested in it the current style and decorators of their group.
+func (g *Group) refresh() {
+    for _, pb := range g.Bars() {
+        pb.mu.Lock()
+        if pb.group.within(g) {
+            pb.inherit()
+            pb.update()
+        }
+        pb.mu.Unlock()
+    }
+}
+
+// within will determine whether the group is the specified group or
+// nested in it. A nil group is within no group.
+func (g *Group) within(ancestor *Group) bool {
+    for ; g != nil; g = g.parent {
+        if g == ancestor {
+            return true
+        }
+    }
+
+    return false
+}
+
+// defaults will retrieve the style the progress bars of the group
+// inherit, or nil if neither the group nor its parents set one, and
+// the decorators they inherit, those of the outermost group first.
+func (g *Group) defaults() (*Style, []Decorator) {
+    var style *Style
+    var decorators []Decorator
+    for ; g != nil; g = g.parent {
+        g.mu.Lock()
+        if style == nil {
+            style = g.style
+        }
+        decorators = append(append([]Decorator(nil), g.decorators...),
+            decorators...)
+        g.mu.Unlock()
+    }
+
+    return style, decorators
+}
+
+// SetGroupDecorators will set whether the progress bar displays the
+// decorators of the Group it belongs to, in front of its own. They
+// are displayed by default.
+func (pb *ProgressBar) SetGroupDecorators(inherit bool) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    pb.hideGroupDecorators = !inherit
+    pb.update()
+}
+
+// inherit will give the progress bar the style and decorators of its
+// group. The distance markers of the progress bar are kept if the
+// group's style has none. The caller must hold pb.mu.
+func (pb *ProgressBar) inherit() {
+    style, decorators := pb.group.defaults()
+    if style != nil && !pb.ownStyle && !pb.finished {
+        inherited := *style
+        if inherited.MarkerInterval <= 0 {
+            inherited.MarkerChar = pb.style.MarkerChar
+            inherited.MarkerInterval = pb.style.MarkerInterval
+        }
+
+        pb.style = inherited
+        pb.restyle()
+    }
+
+    pb.groupDecorators = pb.groupDecorators[:0]
+    for _, d := range decorators {
+        pb.groupDecorators = append(pb.groupDecorators,
+            decoratorEntry{decorate: d})
+    }
+}
@@ -0,0 +1,126 @@
+package progresscli
+
+import (
+    "fmt"
+    "math"
+    "sort"
+    "strings"
+    "time"
+)
+
+// histogramBuckets is the number of buckets, and so the width in
+// columns, of the histogram of a Distribution.
+const histogramBuckets = 8
+
+// histogramBars are the characters drawing the buckets of a histogram,
+// from lowest to highest. Empty buckets are drawn as spaces, so that
+// outliers stand out.
+var histogramBars = []string{"▁", "▂", "▃", "▄", "▅", "▆", "▇", "█"}
+
+// Distribution summarizes how long the jobs of a run took, see
+// Results.Durations().
+type Distribution struct {
+    // Count is the number of jobs.
+    Count   int
+
+    // Min, P50, P95 and Max are the shortest, median, 95th percentile
+    // and longest durations of the jobs.
+    Min     time.Duration
+    P50     time.Duration
+    P95     time.Duration
+    Max     time.Duration
+
+    // Buckets holds the number of jobs whose duration falls in each of
+    // a number of equally wide ranges between Min and Max, shortest
+    // first.
+    Buckets []int
+}
+
+// Durations will compute the distribution of the durations of the
+// jobs, for example to spot jobs that took much longer than the rest.
+func (rs Results) Durations() Distribution {
+    durations := make([]time.Duration, len(rs))
+    for i, r := range rs {
+        durations[i] = r.Duration
+    }
+
+    sort.Slice(durations, func(i, j int) bool {
+        return durations[i] < durations[j]
+    })
+
+    d := Distribution{Count: len(durations)}
+    if d.Count == 0 {
+        return d
+    }
+
+    d.Min = durations[0]
+    d.Max = durations[d.Count-1]
+    d.P50 = percentile(durations, 50)
+    d.P95 = percentile(durations, 95)
+
+    d.Buckets = make([]int, histogramBuckets)
+    span := d.Max - d.Min
+    for _, duration := range durations {
+        i := 0
+        if span > 0 {
+            i = int(float64(duration-d.Min) / float64(span) *
+                histogramBuckets)
+        }
+        if i >= histogramBuckets {
+            i = histogramBuckets - 1
+        }
+        d.Buckets[i]++
+    }
+
+    return d
+}
+
+// Histogram will draw the buckets of the distribution as a row of
+// block characters whose height is proportional to the number of jobs
+// in the bucket, e.g. "█▃    ▁".
+func (d Distribution) Histogram() string {
+    var highest int
+    for _, n := range d.Buckets {
+        if n > highest {
+            highest = n
+        }
+    }
+
+    var b strings.Builder
+    for _, n := range d.Buckets {
+        if n == 0 {
+            b.WriteString(" ")
+            continue
+        }
+
+        level := int(math.Ceil(float64(n)/float64(highest)*
+            float64(len(histogramBars)))) - 1
+        b.WriteString(histogramBars[level])
+    }
+
+    return b.String()
+}
+
+// String will describe the distribution on a single line, e.g.
+// "12 jobs, p50 1.204s, p95 4.52s, max 9.1s ▇█▂    ▁".
+func (d Distribution) String() string {
+    if d.Count == 0 {
+        return "0 jobs"
+    }
+
+    histogram := strings.TrimRight(d.Histogram(), " ")
+    return fmt.Sprintf("%d jobs, p50 %s, p95 %s, max %s %s", d.Count,
+        d.P50.Round(time.Millisecond), d.P95.Round(time.Millisecond),
+        d.Max.Round(time.Millisecond), histogram)
+}
+
+// percentile will pick the p-th percentile of the sorted durations
+// using the nearest-rank method.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+    rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+    if rank < 1 {
+        rank = 1
+    }
+
+    return sorted[rank-1]
+}
@@ -0,0 +1,246 @@
+package progresscli
+
+import (
+    "fmt"
+    "html"
+    "strconv"
+    "strings"
+)
+
+// HTMLRenderer renders a static HTML snapshot of a progress bar, for
+// embedding in reports or emails generated after a run. The layout is
+// the same as that of the LineRenderer and the ANSI colors of the
+// progress bar's style are translated into inline CSS.
+//
+// The HTMLRenderer is usually used with Snapshot() rather than
+// SetRenderer(), since its output is not meant for a terminal.
+type HTMLRenderer struct {
+    // Width is the width of the snapshot in columns. If zero, the
+    // width of the progress bar is used.
+    Width int
+
+    // Class is the CSS class of the enclosing <pre> element. If
+    // empty, "progresscli" is used.
+    Class string
+}
+
+// Frame will render an HTML snapshot of the progress bar.
+func (r HTMLRenderer) Frame(s State) ([]byte, error) {
+    if r.Width > 0 {
+        s.Width = r.Width
+    }
+
+    line, err := NewLineRenderer().Frame(s)
+    if err != nil {
+        return nil, err
+    }
+
+    class := r.Class
+    if class == "" {
+        class = "progresscli"
+    }
+
+    var out strings.Builder
+    fmt.Fprintf(&out, `<pre class="%s">`, html.EscapeString(class))
+    out.WriteString(ansiToHTML(string(line)))
+    out.WriteString("</pre>")
+    return []byte(out.String()), nil
+}
+
+// Snapshot will render the current state of the progress bar using
+// the specified renderer and return the result, without writing it to
+// the progress bar's writer or affecting its animation.
+func (pb *ProgressBar) Snapshot(r Renderer) ([]byte, error) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    state := pb.state()
+    state.Decorations = pb.decorate(state, false)
+
+    return r.Frame(state)
+}
+
+// ansiPalette holds the CSS colors of the 16 basic ANSI colors.
+var ansiPalette = [16]string{
+    "#000000", "#cd3131", "#0dbc79", "#e5e510",
+    "#2472c8", "#bc3fbc", "#11a8cd", "#e5e5e5",
+    "#666666", "#f14c4c", "#23d18b", "#f5f543",
+    "#3b8eea", "#d670d6", "#29b8db", "#ffffff",
+}
+
+// sgrState tracks the text attributes set by SGR escape sequences.
+type sgrState struct {
+    fg, bg                       string
+    bold, dim, italic, underline bool
+}
+
+func (st sgrState) css() string {
+    var rules []string
+    if st.fg != "" {
+        rules = append(rules, "color:"+st.fg)
+    }
+    if st.bg != "" {
+        rules = append(rules, "background-color:"+st.bg)
+    }
+    if st.bold {
+        rules = append(rules, "font-weight:bold")
+    }
+    if st.dim {
+        rules = append(rules, "opacity:0.6")
+    }
+    if st.italic {
+        rules = append(rules, "font-style:italic")
+    }
+    if st.underline {
+        rules = append(rules, "text-decoration:underline")
+    }
+
+    return strings.Join(rules, ";")
+}
+
+// ansiToHTML will convert text containing SGR escape sequences into
+// HTML, wrapping styled runs of text in <span> elements. Adjacent
+// runs with the same style share a single element. All other escape
+// sequences are removed.
+func ansiToHTML(s string) string {
+    var out strings.Builder
+    var st sgrState
+    var current string
+
+    for len(s) > 0 {
+        loc := ansi_re.FindStringIndex(s)
+        text := s
+        if loc != nil {
+            text = s[:loc[0]]
+        }
+
+        if text != "" {
+            if css := st.css(); css != current {
+                if current != "" {
+                    out.WriteString("</span>")
+                }
+                if css != "" {
+                    fmt.Fprintf(&out, `<span style="%s">`, css)
+                }
+                current = css
+            }
+            out.WriteString(html.EscapeString(text))
+        }
+
+        if loc == nil {
+            break
+        }
+
+        seq := s[loc[0]:loc[1]]
+        s = s[loc[1]:]
+        if strings.HasSuffix(seq, "m") {
+            st.apply(seq)
+        }
+    }
+
+    if current != "" {
+        out.WriteString("</span>")
+    }
+
+    return out.String()
+}
+
+// apply will update the state with the parameters of an SGR sequence
+// such as "\033[1;32m".
+func (st *sgrState) apply(seq string) {
+    params := strings.TrimLeft(seq, "\u001b\u009b[")
+    params = strings.TrimSuffix(params, "m")
+    if params == "" {
+        *st = sgrState{}
+        return
+    }
+
+    codes := strings.Split(params, ";")
+    for i := 0; i < len(codes); i++ {
+        code, err := strconv.Atoi(codes[i])
+        if err != nil {
+            continue
+        }
+
+        switch {
+        case code == 0:
+            *st = sgrState{}
+        case code == 1:
+            st.bold = true
+        case code == 2:
+            st.dim = true
+        case code == 3:
+            st.italic = true
+        case code == 4:
+            st.underline = true
+        case code == 22:
+            st.bold, st.dim = false, false
+        case code == 23:
+            st.italic = false
+        case code == 24:
+            st.underline = false
+        case code >= 30 && code <= 37:
+            st.fg = ansiPalette[code-30]
+        case code >= 90 && code <= 97:
+            st.fg = ansiPalette[code-90+8]
+        case code == 39:
+            st.fg = ""
+        case code >= 40 && code <= 47:
+            st.bg = ansiPalette[code-40]
+        case code >= 100 && code <= 107:
+            st.bg = ansiPalette[code-100+8]
+        case code == 49:
+            st.bg = ""
+        case code == 38 || code == 48:
+            color, n := extendedColor(codes[i+1:])
+            i += n
+            if code == 38 {
+                st.fg = color
+            } else {
+                st.bg = color
+            }
+        }
+    }
+}
+
+// extendedColor will parse the parameters following a 38 or 48 SGR
+// code, returning the CSS color and the number of parameters used.
+func extendedColor(params []string) (string, int) {
+    if len(params) >= 2 && params[0] == "5" {
+        n, err := strconv.Atoi(params[1])
+        if err != nil || n < 0 || n > 255 {
+            return "", 2
+        }
+
+        return xterm256(n), 2
+    }
+
+    if len(params) >= 4 && params[0] == "2" {
+        var rgb [3]int
+        for i := range rgb {
+            rgb[i], _ = strconv.Atoi(params[i+1])
+        }
+
+        return fmt.Sprintf("#%02x%02x%02x", rgb[0], rgb[1], rgb[2]), 4
+    }
+
+    return "", len(params)
+}
+
+// xterm256 will convert a color of the xterm 256 color palette into
+// a CSS color.
+func xterm256(n int) string {
+    if n < 16 {
+        return ansiPalette[n]
+    }
+
+    if n >= 232 {
+        v := 8 + (n-232)*10
+        return fmt.Sprintf("#%02x%02x%02x", v, v, v)
+    }
+
+    n -= 16
+    levels := [6]int{0, 95, 135, 175, 215, 255}
+    return fmt.Sprintf("#%02x%02x%02x",
+        levels[n/36], levels[(n/6)%6], levels[n%6])
+}
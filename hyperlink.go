@@ -0,0 +1,10 @@
+package progresscli
+
+// Hyperlink will wrap text in an OSC 8 hyperlink sequence pointing at
+// url, so that it can be clicked in terminals that support hyperlinks,
+// for example in a label or decorator. Terminals without support
+// display the text only. Only the text is counted when measuring the
+// width of the progress bar.
+func Hyperlink(url, text string) string {
+    return "\033]8;;" + url + "\033\\" + text + "\033]8;;\033\\"
+}
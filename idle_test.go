@@ -0,0 +1,109 @@
+package progresscli_test
+
+import (
+    "io"
+    "runtime"
+    "testing"
+    "time"
+
+    "github.com/nathan-fiscaletti/progresscli-go"
+    "github.com/nathan-fiscaletti/progresscli-go/progresstest"
+)
+
+// waitGoroutines will wait for the number of running goroutines to
+// drop to n, failing the test if it doesn't within a second. Goroutines
+// that were told to stop may take a moment to return.
+func waitGoroutines(t *testing.T, n int) {
+    t.Helper()
+
+    deadline := time.Now().Add(time.Second)
+    for runtime.NumGoroutine() > n {
+        if time.Now().After(deadline) {
+            t.Fatalf("%d goroutines still running, want %d",
+                runtime.NumGoroutine(), n)
+        }
+        time.Sleep(10 * time.Millisecond)
+    }
+}
+
+// newIdleBar will create a progress bar that starts a ticker and a
+// resize watcher while it is running.
+func newIdleBar() *progresscli.ProgressBar {
+    pb := progresscli.New()
+    pb.SetRefreshInterval(10 * time.Millisecond)
+    pb.SetSizeProvider(progresstest.NewFakeSize(80, 24))
+    return pb
+}
+
+func TestIdleAfterFinish(t *testing.T) {
+    base := runtime.NumGoroutine()
+
+    pb := newIdleBar()
+    pb.ShowIn(io.Discard)
+    if runtime.NumGoroutine() <= base {
+        t.Fatal("running progress bar started no goroutines")
+    }
+
+    pb.SetValue(100)
+    waitGoroutines(t, base)
+}
+
+func TestIdleAfterAbort(t *testing.T) {
+    base := runtime.NumGoroutine()
+
+    pb := newIdleBar()
+    pb.ShowIn(io.Discard)
+    pb.Abort()
+    waitGoroutines(t, base)
+}
+
+func TestIdleWhilePaused(t *testing.T) {
+    base := runtime.NumGoroutine()
+
+    pb := newIdleBar()
+    pb.ShowIn(io.Discard)
+    pb.Pause()
+    waitGoroutines(t, base)
+
+    pb.Resume()
+    if runtime.NumGoroutine() <= base {
+        t.Fatal("resumed progress bar started no goroutines")
+    }
+
+    pb.Abort()
+    waitGoroutines(t, base)
+}
+
+func TestIdleManager(t *testing.T) {
+    base := runtime.NumGoroutine()
+
+    m := progresscli.NewManager()
+    m.SetSizeProvider(progresstest.NewFakeSize(80, 24))
+    a, b := newIdleBar(), newIdleBar()
+    m.Add(a)
+    m.Add(b)
+    m.ShowIn(io.Discard)
+    if runtime.NumGoroutine() <= base {
+        t.Fatal("running manager started no goroutines")
+    }
+
+    // Once every progress bar has ended, nothing runs in the
+    // background, even before the manager is stopped.
+    a.SetValue(100)
+    b.Abort()
+    waitGoroutines(t, base)
+
+    m.Stop()
+    waitGoroutines(t, base)
+}
+
+func TestIdleManagerStopped(t *testing.T) {
+    base := runtime.NumGoroutine()
+
+    m := progresscli.NewManager()
+    m.SetSizeProvider(progresstest.NewFakeSize(80, 24))
+    m.Add(newIdleBar())
+    m.ShowIn(io.Discard)
+    m.Stop()
+    waitGoroutines(t, base)
+}
@@ -0,0 +1,66 @@
+package progresscli
+
+// ItemHistoryCapacity is the number of items set using
+// SetCurrentItem() that a progress bar remembers.
+const ItemHistoryCapacity = 16
+
+// SetCurrentItem will set the item the progress bar is currently
+// processing, such as the name of a file. The most recent items are
+// kept in State.RecentItems, so that decorators such as
+// RecentItemsDecorator() can display them.
+func (pb *ProgressBar) SetCurrentItem(item string) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    // The items are never modified in place, since states taken
+    // earlier share them.
+    pb.items = append(pb.items, item)
+    if len(pb.items) > ItemHistoryCapacity {
+        pb.items = pb.items[1:]
+    }
+
+    pb.update()
+}
+
+// CurrentItemDecorator will create a decorator that displays the item
+// set using SetCurrentItem().
+func CurrentItemDecorator() Decorator {
+    return func(s State) string {
+        return s.CurrentItem()
+    }
+}
+
+// RecentItemsDecorator will create a decorator that cycles through the
+// last n items set using SetCurrentItem(), displaying the next one each
+// time it is called, so that recently processed items stream by
+// without a status line of their own. Add it using AddTimedDecorator()
+// to advance one item per interval, even while the progress bar
+// doesn't change. At most ItemHistoryCapacity items are cycled
+// through. Each progress bar needs a decorator of its own.
+func RecentItemsDecorator(n int) Decorator {
+    next := 0
+    return func(s State) string {
+        items := s.RecentItems
+        if n > 0 && len(items) > n {
+            items = items[len(items)-n:]
+        }
+
+        if len(items) == 0 {
+            return ""
+        }
+
+        item := items[next%len(items)]
+        next++
+        return item
+    }
+}
+
+// CurrentItem will retrieve the item set last using SetCurrentItem(),
+// or an empty string if none has been set.
+func (s State) CurrentItem() string {
+    if len(s.RecentItems) == 0 {
+        return ""
+    }
+
+    return s.RecentItems[len(s.RecentItems)-1]
+}
@@ -0,0 +1,30 @@
+package progresscli
+
+import (
+    "io"
+)
+
+// LastFrame will retrieve the exact bytes written to the writer for
+// the most recent frame of the progress bar, including the escape
+// sequences clearing and positioning the line, for golden-file tests
+// and debugging without intercepting the writer. For a progress bar
+// that belongs to a Manager, it is the frame passed to the manager,
+// without the sequences the manager positions its rows with. If no
+// frame has been written yet, nil is returned.
+func (pb *ProgressBar) LastFrame() []byte {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    if pb.lastFrame == nil {
+        return nil
+    }
+
+    return append([]byte(nil), pb.lastFrame...)
+}
+
+// writeFrame will write a frame to the progress bar's writer, keeping
+// a copy of it for LastFrame(). The caller must hold pb.mu.
+func (pb *ProgressBar) writeFrame(frame string) (int, error) {
+    pb.lastFrame = append(pb.lastFrame[:0], frame...)
+    return io.WriteString(pb.writer, frame)
+}
@@ -0,0 +1,89 @@
+package progresscli
+
+import (
+    "time"
+)
+
+const (
+    // slowWrite is the average write latency above which frames are
+    // drawn less often.
+    slowWrite = time.Millisecond
+
+    // writeBudget is the share of time, as a fraction 1/writeBudget,
+    // that writing frames may take up when writes are slow.
+    writeBudget = 10
+)
+
+// writeLatency tracks how long writing frames to a writer takes, in
+// order to draw them less often when writes are slow, for example over
+// a slow SSH connection or a serial console.
+type writeLatency struct {
+    average time.Duration
+    last    time.Time
+}
+
+// observe will record a write that started at the specified time and
+// completed at now.
+func (l *writeLatency) observe(start, now time.Time) {
+    l.average = (3*l.average + now.Sub(start)) / 4
+    l.last = now
+}
+
+// backoff will determine whether the next frame should be skipped at
+// now, because writes are slow and the previous one was written too
+// recently.
+func (l *writeLatency) backoff(now time.Time) bool {
+    if l.average < slowWrite {
+        return false
+    }
+
+    return now.Sub(l.last) < l.average*writeBudget
+}
+
+// SetAdaptiveRefresh will set whether the progress bar draws frames
+// less often when writing them is slow. When enabled, which is the
+// default, the time it takes to write each frame is measured and, if
+// writes take more than a millisecond on average, intermediate frames
+// are skipped so that drawing takes up at most a tenth of the time.
+// The final frame is always drawn, as is the last frame skipped before
+// the progress bar is paused or aborted. Adaptive refresh is not
+// applied to progress bars using a FrameClock, so their output stays
+// deterministic.
+func (pb *ProgressBar) SetAdaptiveRefresh(enabled bool) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    pb.adaptive = enabled
+}
+
+// backoff will determine whether the next frame of the progress bar
+// should be skipped because writes to its writer are slow. The caller
+// must hold pb.mu.
+func (pb *ProgressBar) backoff() bool {
+    if !pb.adaptive || pb.complete() {
+        return false
+    }
+
+    if _, ok := pb.clock.(*FrameClock); ok {
+        return false
+    }
+
+    if !pb.latency.backoff(pb.now()) {
+        return false
+    }
+
+    pb.backedOff = true
+    return true
+}
+
+// drawBackedOff will draw the frame of the progress bar that was
+// skipped last because writes are slow, if it hasn't been drawn since,
+// so that a progress bar that stops being updated doesn't keep showing
+// an older frame. The caller must hold pb.mu.
+func (pb *ProgressBar) drawBackedOff() {
+    if !pb.backedOff || pb.manager != nil || pb.suspend.active() {
+        return
+    }
+
+    pb.draw()
+}
@@ -0,0 +1,148 @@
+package progresscli
+
+import (
+    "fmt"
+    "regexp"
+    "strings"
+)
+
+// Warning describes a problem found in a Style by Lint().
+type Warning struct {
+    // Field is the name of the style's field the problem was found
+    // in, e.g. "DoneChar" or "Spinner[2]".
+    Field   string
+
+    // Message describes the problem.
+    Message string
+}
+
+// String will format the warning as "Field: Message".
+func (w Warning) String() string {
+    return w.Field + ": " + w.Message
+}
+
+// cursor_re matches escape sequences that move the cursor, clear part
+// of the screen or change the terminal's modes.
+var cursor_re = regexp.MustCompile(
+    "\u001b(?:\\[[0-9;?]*[ABCDEFGHJKSTfhlsu]|[78])")
+
+// sgr_re matches SGR escape sequences, which set the color and other
+// attributes of text.
+var sgr_re = regexp.MustCompile("\u001b\\[[0-9;]*m")
+
+// Lint will check the style for common mistakes that break the layout
+// of a progress bar, such as escape sequences that move the cursor,
+// colors that are never reset and characters that are not counted
+// correctly when measuring the width of the bar. If no problems are
+// found, nil is returned.
+func (s Style) Lint() []Warning {
+    var warnings []Warning
+
+    fields := []struct {
+        name      string
+        value     string
+        needReset bool
+        single    bool
+    }{
+        {"OpenChar", s.OpenChar, true, false},
+        {"CloseChar", s.CloseChar, true, false},
+        {"FinishedOpenChar", s.FinishedOpenChar, true, false},
+        {"FinishedCloseChar", s.FinishedCloseChar, true, false},
+        {"DoneChar", s.DoneChar, true, true},
+        {"NotDoneChar", s.NotDoneChar, true, true},
+        {"InProgressChar", s.InProgressChar, true, false},
+        {"FailedChar", s.FailedChar, true, true},
+        {"SkippedChar", s.SkippedChar, true, true},
+        {"MarkerChar", s.MarkerChar, true, false},
+        {"PercentageColor", s.PercentageColor, false, false},
+        {"VerbColor", s.VerbColor, false, false},
+        {"TrackColor", s.TrackColor, false, false},
+        {"Padding", s.Padding, true, false},
+    }
+
+    for _, f := range fields {
+        warnings = append(warnings,
+            lintComponent(f.name, f.value, f.needReset)...)
+
+        if f.single && f.value != "" && strLen(f.value) != 1 {
+            warnings = append(warnings, Warning{f.name, fmt.Sprintf(
+                "is %d columns wide; fill characters must be exactly "+
+                "one column wide", strLen(f.value))})
+        }
+    }
+
+    if strLen(s.DoneChar) == 0 {
+        warnings = append(warnings, Warning{"DoneChar",
+            "is empty; the completed section of the bar is invisible"})
+    }
+
+    for i, frame := range s.Spinner {
+        name := fmt.Sprintf("Spinner[%d]", i)
+        warnings = append(warnings, lintComponent(name, frame, true)...)
+
+        if strLen(frame) != strLen(s.Spinner[0]) {
+            warnings = append(warnings, Warning{name, fmt.Sprintf(
+                "is %d columns wide but Spinner[0] is %d; frames of "+
+                "different widths make the bar jitter",
+                strLen(frame), strLen(s.Spinner[0]))})
+        }
+    }
+
+    return warnings
+}
+
+// lintComponent will check a single component of a style. If
+// needReset is true, the component must reset any attributes it sets,
+// since it is followed by other components.
+func lintComponent(name, value string, needReset bool) []Warning {
+    var warnings []Warning
+
+    if seq := cursor_re.FindString(value); seq != "" {
+        warnings = append(warnings, Warning{name, fmt.Sprintf(
+            "contains the cursor control sequence %q, which moves the "+
+            "cursor or clears part of the line", seq)})
+    }
+
+    if needReset {
+        sgrs := sgr_re.FindAllString(value, -1)
+        if len(sgrs) > 0 && !isReset(sgrs[len(sgrs)-1]) {
+            warnings = append(warnings, Warning{name,
+                `sets text attributes without resetting them with ` +
+                `"\033[0m", so they leak into the rest of the line`})
+        }
+    }
+
+    // The stripper accepts a CSI sequence ending in a digit, while the
+    // terminal waits for a final byte and swallows the next character.
+    for _, seq := range ansi_re.FindAllString(value, -1) {
+        last := seq[len(seq)-1]
+        if strings.HasPrefix(seq, "\u001b[") && last >= '0' && last <= '9' {
+            warnings = append(warnings, Warning{name, fmt.Sprintf(
+                "contains the incomplete escape sequence %q, which "+
+                "swallows the character following it", seq)})
+            break
+        }
+    }
+
+    // Anything left after removing the escape sequences is counted as
+    // one column per character, which is wrong for control characters
+    // and the remains of malformed escape sequences.
+    for _, c := range ansi_re.ReplaceAllString(value, "") {
+        if c < 0x20 || c == 0x7f || (c >= 0x80 && c < 0xa0) {
+            warnings = append(warnings, Warning{name, fmt.Sprintf(
+                "contains the control character %q, which is "+
+                "counted as a visible column; escape sequences may be "+
+                "malformed", c)})
+            break
+        }
+    }
+
+    return warnings
+}
+
+// isReset will determine whether an SGR sequence resets all text
+// attributes.
+func isReset(seq string) bool {
+    params := strings.TrimSuffix(strings.TrimPrefix(seq, "\u001b["), "m")
+    return params == "" || strings.Trim(params, "0") == ""
+}
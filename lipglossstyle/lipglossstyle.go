@@ -0,0 +1,136 @@
+// Package lipglossstyle converts styles defined with
+// github.com/charmbracelet/lipgloss into a progresscli.Style, so that
+// progress bars can be themed along with the rest of a charm based
+// interface.
+package lipglossstyle
+
+import (
+    "regexp"
+    "strings"
+
+    "github.com/charmbracelet/lipgloss"
+    "github.com/nathan-fiscaletti/progresscli-go"
+)
+
+// sgr_re matches SGR escape sequences, which set the color and other
+// attributes of text.
+var sgr_re = regexp.MustCompile("\u001b\\[[0-9;]*m")
+
+// Styles holds the lipgloss style of each element of a progress bar.
+// Only the foreground color, background color and bold attribute of
+// each style are used. Elements whose style sets none of them keep the
+// sequences of the base style.
+type Styles struct {
+    // Caps is applied to the open and close characters.
+    Caps         lipgloss.Style
+
+    // FinishedCaps is applied to the open and close characters once
+    // the progress bar is complete.
+    FinishedCaps lipgloss.Style
+
+    // Done, NotDone, InProgress, Failed and Skipped are applied to the
+    // characters of the corresponding sections of the bar. InProgress
+    // is applied to the frames of the spinner as well.
+    Done         lipgloss.Style
+    NotDone      lipgloss.Style
+    InProgress   lipgloss.Style
+    Failed       lipgloss.Style
+    Skipped      lipgloss.Style
+
+    // Track is used for the track color of the style. Only its
+    // background color is used.
+    Track        lipgloss.Style
+
+    // Percentage and Verb are applied to the percentage and the verb.
+    Percentage   lipgloss.Style
+    Verb         lipgloss.Style
+}
+
+// Convert will apply styles to the characters of base and retrieve the
+// resulting style. The escape sequences are produced by lipgloss, so
+// they follow the color profile of its default renderer and are left
+// out if colors are not supported.
+func Convert(base progresscli.Style, styles Styles) progresscli.Style {
+    s := base
+    s.OpenChar = render(styles.Caps, base.OpenChar)
+    s.CloseChar = render(styles.Caps, base.CloseChar)
+
+    if finished, ok := attributes(styles.FinishedCaps); ok {
+        open, close := base.FinishedOpenChar, base.FinishedCloseChar
+        if open == "" {
+            open = base.OpenChar
+        }
+        if close == "" {
+            close = base.CloseChar
+        }
+
+        s.FinishedOpenChar = render(finished, open)
+        s.FinishedCloseChar = render(finished, close)
+    }
+
+    s.DoneChar = render(styles.Done, base.DoneChar)
+    s.NotDoneChar = render(styles.NotDone, base.NotDoneChar)
+    s.InProgressChar = render(styles.InProgress, base.InProgressChar)
+    s.FailedChar = render(styles.Failed, base.FailedChar)
+    s.SkippedChar = render(styles.Skipped, base.SkippedChar)
+
+    if len(base.Spinner) > 0 {
+        s.Spinner = make([]string, len(base.Spinner))
+        for i, frame := range base.Spinner {
+            s.Spinner[i] = render(styles.InProgress, frame)
+        }
+    }
+
+    if background, ok := attributes(
+        lipgloss.NewStyle().Background(styles.Track.GetBackground()),
+    ); ok {
+        s.TrackColor = sequence(background)
+    }
+
+    if percentage, ok := attributes(styles.Percentage); ok {
+        s.PercentageColor = sequence(percentage)
+    }
+
+    if verb, ok := attributes(styles.Verb); ok {
+        s.VerbColor = sequence(verb)
+    }
+
+    return s
+}
+
+// attributes will retrieve a style with only the foreground color,
+// background color and bold attribute of st. If st sets none of them,
+// false is returned.
+func attributes(st lipgloss.Style) (lipgloss.Style, bool) {
+    _, noForeground := st.GetForeground().(lipgloss.NoColor)
+    _, noBackground := st.GetBackground().(lipgloss.NoColor)
+    if noForeground && noBackground && !st.GetBold() {
+        return st, false
+    }
+
+    return lipgloss.NewStyle().
+        Foreground(st.GetForeground()).
+        Background(st.GetBackground()).
+        Bold(st.GetBold()), true
+}
+
+// render will render char using the attributes of st. The colors
+// already in char are removed first. If st sets no
+// attributes, char is returned unchanged.
+func render(st lipgloss.Style, char string) string {
+    st, ok := attributes(st)
+    if !ok || char == "" {
+        return char
+    }
+
+    return st.Render(sgr_re.ReplaceAllString(char, ""))
+}
+
+// sequence will retrieve the escape sequences lipgloss places before
+// text rendered using st.
+func sequence(st lipgloss.Style) string {
+    const marker = "x"
+
+    rendered := st.Render(marker)
+    return rendered[:strings.Index(rendered, marker)]
+}
@@ -0,0 +1,409 @@
+package progresscli
+
+import (
+    "bytes"
+    "fmt"
+    "io"
+    "sync"
+    "time"
+)
+
+// Manager renders several progress bars at once, each on its own row.
+// Whenever one of the progress bars it manages changes, the manager
+// redraws all of its rows in place. You should initialize a new
+// manager using the NewManager() function. A Manager is safe for
+// concurrent use.
+type Manager struct {
+    // mu protects the fields below. A progress bar's own lock is
+    // always acquired before the lock of its manager, never after.
+    mu         sync.Mutex
+    writer     io.Writer
+    bars       []*ProgressBar
+    frames     map[*ProgressBar]string
+    names      map[string]*ProgressBar
+    ends       map[*ProgressBar]bool
+    cond       *sync.Cond
+    drawn      []string
+    lines      int
+    visible    bool
+    buf        bytes.Buffer
+    latency    writeLatency
+    autoFlush  bool
+    forward    bool
+    mode       Mode
+    sizes      SizeProvider
+    resized    <-chan struct{}
+    resizeDone chan struct{}
+    suspend    suspension
+    throughput throughput
+    summaryRow func(Throughput) string
+    lineBudget int
+    scheduler  Scheduler
+    scheduled  bool
+}
+
+// NewManager will create a new Manager without any progress bars.
+func NewManager() *Manager {
+    m := &Manager{
+        frames: map[*ProgressBar]string{},
+        names: map[string]*ProgressBar{},
+        ends: map[*ProgressBar]bool{},
+        forward: forwarding(),
+        throughput: newThroughput(),
+    }
+    m.cond = sync.NewCond(&m.mu)
+    return m
+}
+
+// Add will add a progress bar to the manager. The progress bar is
+// displayed in a new row below the existing rows. Progress bars
+// added to a manager should not be shown individually.
+func (m *Manager) Add(pb *ProgressBar) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    m.attach(pb, len(m.bars))
+    m.requestDraw()
+}
+
+// attach will add a progress bar to the manager in the row at the
+// specified index, without redrawing the manager. The caller must hold
+// pb.mu and m.mu.
+func (m *Manager) attach(pb *ProgressBar, index int) {
+    pb.manager = m
+    pb.writer = m.writer
+    pb.scheduler = m.scheduler
+    if m.sizes != nil {
+        pb.sizes = m.sizes
+    }
+    pb.visible = m.visible
+    m.applyMode(pb)
+    pb.finished = false
+    pb.paused = false
+    pb.aborted = false
+
+    // Progress bars added to a hidden manager start once it is shown.
+    if pb.visible {
+        pb.start()
+        pb.startTicker()
+    } else {
+        pb.started = time.Time{}
+    }
+
+    m.bars = append(m.bars, nil)
+    copy(m.bars[index+1:], m.bars[index:])
+    m.bars[index] = pb
+    m.ends[pb] = false
+    m.checkIdle()
+    m.throughput.observe(pb)
+    m.frames[pb], _ = pb.render()
+}
+
+// AddNamed will add a progress bar to the manager under the specified
+// name, so that it can later be retrieved using Get() by code that has
+// no reference to it. If a progress bar with the same name has already
+// been added, ErrDuplicateName is returned and the progress bar is not
+// added.
+func (m *Manager) AddNamed(name string, pb *ProgressBar) error {
+    m.mu.Lock()
+    if _, ok := m.names[name]; ok {
+        m.mu.Unlock()
+        return ErrDuplicateName
+    }
+    m.names[name] = pb
+    m.mu.Unlock()
+
+    m.Add(pb)
+    return nil
+}
+
+// Get will retrieve the progress bar added to the manager under the
+// specified name. If there is none, false is returned.
+func (m *Manager) Get(name string) (*ProgressBar, bool) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    pb, ok := m.names[name]
+    return pb, ok
+}
+
+// RemoveNamed will remove the progress bar added to the manager under
+// the specified name. If there is none, false is returned.
+func (m *Manager) RemoveNamed(name string) bool {
+    pb, ok := m.Get(name)
+    if !ok {
+        return false
+    }
+
+    m.Remove(pb)
+    return true
+}
+
+// Remove will remove a progress bar from the manager. The rows below
+// it will move up to take its place.
+func (m *Manager) Remove(pb *ProgressBar) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    m.detach(pb)
+    m.requestDraw()
+}
+
+// replace will remove a progress bar from the manager and display
+// another one in its row, so that the rows below it stay in place. The
+// new progress bar must not be in use elsewhere yet, so that it can be
+// locked along with the one it replaces.
+func (m *Manager) replace(old, pb *ProgressBar) {
+    old.mu.Lock()
+    defer old.mu.Unlock()
+
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    index := m.detach(old)
+    if index < 0 {
+        index = len(m.bars)
+    }
+
+    m.attach(pb, index)
+    m.requestDraw()
+}
+
+// detach will remove a progress bar from the manager without redrawing
+// the manager, and return the index of the row it was displayed in, or
+// -1 if it wasn't. The caller must hold pb.mu and m.mu.
+func (m *Manager) detach(pb *ProgressBar) int {
+    index := -1
+    for i, bar := range m.bars {
+        if bar == pb {
+            m.bars = append(m.bars[:i], m.bars[i+1:]...)
+            index = i
+            break
+        }
+    }
+
+    delete(m.frames, pb)
+    delete(m.ends, pb)
+    m.throughput.forget(pb)
+    m.checkIdle()
+    m.cond.Broadcast()
+    for name, bar := range m.names {
+        if bar == pb {
+            delete(m.names, name)
+        }
+    }
+
+    pb.manager = nil
+    pb.ownLines = false
+    pb.group = nil
+    pb.visible = false
+    pb.stopTicker()
+    return index
+}
+
+// Bars will retrieve the progress bars currently managed by the
+// manager in the order they are displayed.
+func (m *Manager) Bars() []*ProgressBar {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    return append([]*ProgressBar(nil), m.bars...)
+}
+
+// Show will show the manager's progress bars in STDOUT, or in STDERR
+// if STDOUT is redirected, see DefaultWriter().
+func (m *Manager) Show() {
+    m.ShowIn(DefaultWriter())
+}
+
+// ShowIn will show the manager's progress bars in the specified
+// io.Writer.
+func (m *Manager) ShowIn(w io.Writer) {
+    m.mu.Lock()
+    m.writer = w
+    m.visible = true
+    m.lines = 0
+    m.drawn = nil
+    m.throughput.reset()
+    m.mu.Unlock()
+
+    for _, pb := range m.Bars() {
+        pb.mu.Lock()
+        pb.writer = w
+        pb.visible = true
+        pb.start()
+        pb.startTicker()
+        frame, _ := pb.render()
+
+        m.mu.Lock()
+        m.frames[pb] = frame
+        m.throughput.observe(pb)
+        m.mu.Unlock()
+        pb.mu.Unlock()
+    }
+
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    m.checkIdle()
+    m.requestDraw()
+}
+
+// Stop will draw the final state of the manager's progress bars and
+// move the cursor below them. The progress bars will no longer be
+// updated after Stop has been called.
+func (m *Manager) Stop() {
+    for _, pb := range m.Bars() {
+        pb.mu.Lock()
+        pb.visible = false
+        pb.stopTicker()
+        pb.mu.Unlock()
+    }
+
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    if !m.visible {
+        return
+    }
+
+    m.draw()
+    if m.inPlace() && !m.suspend.active() {
+        fmt.Fprint(m.writer, "\n")
+    }
+    if m.autoFlush {
+        flush(m.writer)
+    }
+    m.visible = false
+    m.lines = 0
+    m.drawn = nil
+    m.checkIdle()
+}
+
+// update will store the latest frame of a progress bar and redraw
+// the manager, unless writes to its writer are slow and it was redrawn
+// too recently. It is called by progress bars holding their own lock.
+func (m *Manager) update(pb *ProgressBar, frame string) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    m.frames[pb] = frame
+    m.ended(pb, pb.finished)
+    m.throughput.observe(pb)
+
+    // The frame is kept, so skipping a redraw because writes are slow
+    // only delays it until the next one. The final frame of a progress
+    // bar is never delayed.
+    if pb.adaptive && !pb.finished && m.latency.backoff(time.Now()) {
+        return
+    }
+
+    m.requestDraw()
+}
+
+// draw will redraw the rows of the manager in place using the most
+// recent frame of each progress bar. Only rows whose frame changed
+// since they were last written are redrawn, and the whole update is
+// written to the writer at once. The cursor is left on the last row.
+// The caller must hold m.mu.
+func (m *Manager) draw() {
+    // Forwarded progress bars, and those of a manager not in the TTY
+    // mode, write their own lines.
+    if !m.visible || !m.inPlace() || m.suspend.active() {
+        return
+    }
+
+    m.buf.Reset()
+
+    // The cursor is on the last line of the block drawn previously, or
+    // on the line the block is about to be drawn on.
+    cursor := m.lines - 1
+    if cursor < 0 {
+        cursor = 0
+    }
+    height := cursor + 1
+
+    // Progress bars wrapping across several rows take up one line per
+    // row.
+    frames := m.rows()
+
+    // If rows were removed since the last frame, the lines they
+    // occupied still need to be cleared.
+    rows := len(frames)
+    lines := rows
+    if m.lines > lines {
+        lines = m.lines
+    }
+
+    for i := 0; i < lines; i++ {
+        var frame string
+        if i < rows {
+            frame = frames[i]
+        }
+
+        if i < m.lines && i < len(m.drawn) && m.drawn[i] == frame {
+            continue
+        }
+
+        cursor, height = m.moveTo(cursor, height, i)
+        m.buf.WriteString("\r\033[2K")
+        m.buf.WriteString(frame)
+    }
+
+    last := rows - 1
+    if last < 0 {
+        last = 0
+    }
+    m.moveTo(cursor, height, last)
+
+    m.drawn = append(m.drawn[:0], frames...)
+    m.lines = last + 1
+
+    if m.buf.Len() > 0 {
+        start := time.Now()
+        m.writer.Write(m.buf.Bytes())
+        if m.autoFlush {
+            flush(m.writer)
+        }
+        m.latency.observe(start, time.Now())
+    }
+}
+
+// moveTo will append the cursor movement from line cursor to line
+// target of the block to the manager's buffer. Lines at or below
+// height do not exist yet and are created using newlines. The new
+// cursor position and block height are returned.
+func (m *Manager) moveTo(cursor, height, target int) (int, int) {
+    switch {
+    case target < cursor:
+        fmt.Fprintf(&m.buf, "\033[%dA", cursor-target)
+    case target > cursor:
+        if existing := height - 1 - cursor; existing > 0 {
+            if existing > target-cursor {
+                existing = target - cursor
+            }
+            fmt.Fprintf(&m.buf, "\033[%dB", existing)
+            cursor += existing
+        }
+
+        for ; cursor < target; cursor++ {
+            m.buf.WriteString("\n")
+            height++
+        }
+    }
+
+    if target+1 > height {
+        height = target + 1
+    }
+
+    return target, height
+}
@@ -0,0 +1,86 @@
+package progresscli
+
+import (
+    "fmt"
+    "testing"
+)
+
+// countingWriter discards everything written to it, counting the
+// bytes and the calls to Write.
+type countingWriter struct {
+    bytes  int
+    writes int
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+    w.bytes += len(p)
+    w.writes++
+    return len(p), nil
+}
+
+// newBenchmarkManager will create a manager showing the specified
+// number of progress bars in an 80 column terminal.
+func newBenchmarkManager(n int) (*Manager, []*ProgressBar, *countingWriter) {
+    m := NewManager()
+    m.SetSizeProvider(SizeFunc(func() (int, int) {
+        return 80, 200
+    }))
+
+    bars := make([]*ProgressBar, n)
+    for i := range bars {
+        bars[i] = New()
+        bars[i].SetLabel(fmt.Sprintf("job %d", i))
+        bars[i].SetMax(float64(1 << 30))
+        m.Add(bars[i])
+    }
+
+    w := &countingWriter{}
+    m.ShowIn(w)
+    return m, bars, w
+}
+
+// benchmarkManagerFrame will measure a frame of a dashboard refreshed
+// at 30 Hz, in which each of the specified number of progress bars has
+// made progress since the previous frame. Each iteration is one frame,
+// so ns/op has to stay well below the 33ms between two frames.
+func benchmarkManagerFrame(b *testing.B, n int) {
+    m, bars, w := newBenchmarkManager(n)
+    defer m.Stop()
+
+    b.ReportAllocs()
+    b.ResetTimer()
+    for i := 0; i < b.N; i++ {
+        for _, pb := range bars {
+            pb.Increment(1 << 20)
+        }
+    }
+    b.StopTimer()
+
+    b.ReportMetric(float64(w.bytes)/float64(b.N), "bytes/frame")
+    b.ReportMetric(float64(w.writes)/float64(b.N), "writes/frame")
+}
+
+func BenchmarkManagerFrame100(b *testing.B) {
+    benchmarkManagerFrame(b, 100)
+}
+
+func BenchmarkManagerFrame500(b *testing.B) {
+    benchmarkManagerFrame(b, 500)
+}
+
+// BenchmarkManagerFrameOneChanged will measure a frame of a dashboard
+// of 100 progress bars in which only one of them has changed, which
+// should only redraw its row.
+func BenchmarkManagerFrameOneChanged(b *testing.B) {
+    m, bars, w := newBenchmarkManager(100)
+    defer m.Stop()
+
+    b.ReportAllocs()
+    b.ResetTimer()
+    for i := 0; i < b.N; i++ {
+        bars[i%len(bars)].Increment(1 << 20)
+    }
+    b.StopTimer()
+
+    b.ReportMetric(float64(w.bytes)/float64(b.N), "bytes/frame")
+}
@@ -0,0 +1,89 @@
+package progresscli
+
+import (
+    "fmt"
+    "math"
+    "strings"
+)
+
+// markdownEscaper escapes the characters that have a meaning in
+// Markdown inline text.
+var markdownEscaper = strings.NewReplacer(
+    `\`, `\\`, "`", "\\`", `*`, `\*`, `_`, `\_`, `[`, `\[`, `]`, `\]`,
+    `<`, `\<`, `>`, `\>`, `#`, `\#`, `|`, `\|`, `~`, `\~`,
+)
+
+// MarkdownRenderer renders a progress bar as plain text suitable for
+// Markdown contexts such as chat messages or issue comments, for
+// example "Upload ▰▰▰▰▰▰▱▱▱▱ 60%". ANSI escape sequences are removed
+// and the label and decorations are escaped.
+//
+// The MarkdownRenderer is usually used with Snapshot(), so that the
+// progress can be mirrored elsewhere while a terminal bar is drawn.
+type MarkdownRenderer struct {
+    // Cells is the number of cells in the bar. If zero, 10 is used.
+    Cells  int
+
+    // Filled and Empty are the characters used for completed and
+    // remaining cells. If empty, "▰" and "▱" are used.
+    Filled string
+    Empty  string
+}
+
+// Frame will render a Markdown line for the progress bar.
+func (r MarkdownRenderer) Frame(s State) ([]byte, error) {
+    cells := r.Cells
+    if cells <= 0 {
+        cells = 10
+    }
+
+    filled, empty := r.Filled, r.Empty
+    if filled == "" {
+        filled = "▰"
+    }
+    if empty == "" {
+        empty = "▱"
+    }
+
+    done := int(math.Round(s.Fill() * float64(cells)))
+    if done > cells {
+        done = cells
+    }
+    if done < 0 {
+        done = 0
+    }
+
+    bar := strings.Repeat(filled, done) + strings.Repeat(empty, cells-done)
+    if s.Indeterminate {
+        position := s.Frame % cells
+        bar = strings.Repeat(empty, position) + filled +
+              strings.Repeat(empty, cells-position-1)
+    }
+
+    var parts []string
+    if s.Label != "" {
+        parts = append(parts, markdownText(s.Label))
+    }
+
+    parts = append(parts, bar)
+
+    if s.Indeterminate && (s.ShowPercentage || s.ShowPercentageDecimal) {
+        parts = append(parts, "--%")
+    } else if s.ShowPercentageDecimal {
+        parts = append(parts, fmt.Sprintf("%.2f%%", s.Percent))
+    } else if s.ShowPercentage {
+        parts = append(parts, fmt.Sprintf("%.0f%%", s.Percent))
+    }
+
+    for _, text := range s.Decorations {
+        parts = append(parts, markdownText(text))
+    }
+
+    return []byte(strings.Join(parts, " ")), nil
+}
+
+// markdownText will remove ANSI escape sequences from s and escape it
+// for use in Markdown.
+func markdownText(s string) string {
+    return markdownEscaper.Replace(ansi_re.ReplaceAllString(s, ""))
+}
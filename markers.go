@@ -0,0 +1,106 @@
+package progresscli
+
+import (
+    "strconv"
+    "strings"
+)
+
+// DefaultMarkerChar is the glyph drawn by SetDistanceMarkers() when
+// none is specified.
+const DefaultMarkerChar = "┊"
+
+// marker is a distance marker drawn at a column of the fill.
+type marker struct {
+    column int
+    glyph  string
+}
+
+// SetDistanceMarkers will draw a dim mark along the section of the bar
+// that has not been completed yet at every interval percent, e.g. every
+// 10%, making progress easier to read at a glance on very wide
+// terminals. Occurrences of "%d" in the glyph are replaced with the
+// percentage of the mark, so that "%d" labels the marks "10", "20" and
+// so on. An empty glyph draws DefaultMarkerChar, and an interval of
+// zero removes the marks. The marks are stored in the MarkerChar and
+// MarkerInterval of the progress bar's style.
+func (pb *ProgressBar) SetDistanceMarkers(interval float64, glyph string) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    if interval < 0 {
+        interval = 0
+    }
+    if glyph == "" {
+        glyph = DefaultMarkerChar
+    }
+
+    pb.style.MarkerInterval = interval
+    pb.style.MarkerChar = "\033[2m" + glyph + "\033[0m"
+    if interval == 0 {
+        pb.style.MarkerChar = ""
+    }
+
+    pb.restyle()
+    pb.update()
+}
+
+// markers will compute the distance markers of the style for a fill of
+// the specified size, in order. Markers are placed where the fill ends
+// once their percentage is reached, following the scale of the state.
+func markers(s State, size int) []marker {
+    interval := s.Style.MarkerInterval
+    if interval <= 0 || s.Style.MarkerChar == "" || size <= 0 {
+        return nil
+    }
+
+    var found []marker
+    for k := 1; k <= size; k++ {
+        percent := float64(k) * interval
+        if percent >= 100 {
+            break
+        }
+
+        at := State{Percent: percent, Scale: s.Scale}
+        column := int(at.Fill() * float64(size))
+        if n := len(found); n > 0 && found[n-1].column == column {
+            continue
+        }
+
+        glyph := strings.ReplaceAll(s.Style.MarkerChar, "%d",
+            strconv.FormatFloat(percent, 'f', -1, 64))
+        found = append(found, marker{column: column, glyph: glyph})
+    }
+
+    return found
+}
+
+// markedTrack will render the columns from start to end of a fill of
+// the specified size that are not done yet, drawing the distance
+// markers of the style that fit in them in place of the track. The
+// caller must hold r.mu.
+func (r *LineRenderer) markedTrack(s State, start, end, size int) string {
+    found := markers(s, size)
+    if len(found) == 0 {
+        return r.track(s, end-start)
+    }
+
+    var output strings.Builder
+    at := start
+    for _, m := range found {
+        width := strLen(m.glyph)
+        if m.column < at || m.column+width > end {
+            continue
+        }
+
+        output.WriteString(r.track(s, m.column-at))
+        if s.Style.TrackColor != "" {
+            output.WriteString(s.Style.TrackColor + m.glyph + "\033[0m")
+        } else {
+            output.WriteString(m.glyph)
+        }
+        at = m.column + width
+    }
+    output.WriteString(r.track(s, end-at))
+
+    return output.String()
+}
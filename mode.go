@@ -0,0 +1,160 @@
+package progresscli
+
+import (
+    "encoding/json"
+    "errors"
+    "os"
+    "strings"
+)
+
+// ErrUnknownMode is returned by ResolveMode() when the value of a
+// --progress flag doesn't name a mode.
+var ErrUnknownMode = errors.New(
+    "progresscli: unknown progress mode, expected auto, plain, tty, " +
+    "json or none")
+
+// Mode is a way of displaying progress as chosen by users of a CLI,
+// typically with a --progress=auto|plain|tty|json|none flag in the
+// style of docker and buildkit. Use ResolveMode() to parse the value
+// of such a flag and SetMode() to apply it to a progress bar.
+type Mode int
+
+const (
+    // ModeTTY draws the progress bar in place, using the line mode
+    // returned by DetectLineMode().
+    ModeTTY Mode = iota
+
+    // ModePlain writes lines of plain text, see the Plain line mode.
+    ModePlain
+
+    // ModeJSON writes the stats of the progress bar as one JSON
+    // object per line, see the JSON line mode.
+    ModeJSON
+
+    // ModeNone doesn't display the progress bar at all, see the
+    // Hidden line mode.
+    ModeNone
+)
+
+// String will retrieve the flag value selecting the mode, e.g. "tty".
+func (m Mode) String() string {
+    switch m {
+    case ModeTTY:
+        return "tty"
+    case ModePlain:
+        return "plain"
+    case ModeJSON:
+        return "json"
+    case ModeNone:
+        return "none"
+    }
+
+    return "unknown"
+}
+
+// ResolveMode will map the value of a --progress flag to a Mode. The
+// values "tty", "plain", "json" and "none" select the corresponding
+// mode, ignoring case. "auto" and the empty string select ModeTTY if
+// STDOUT or STDERR is a terminal, and ModePlain otherwise, e.g. in CI
+// logs. Any other value, such as a misspelled mode, returns
+// ErrUnknownMode along with the mode "auto" would have selected, so
+// that callers may warn and carry on.
+func ResolveMode(flagValue string) (Mode, error) {
+    switch strings.ToLower(strings.TrimSpace(flagValue)) {
+    case "tty":
+        return ModeTTY, nil
+    case "plain":
+        return ModePlain, nil
+    case "json":
+        return ModeJSON, nil
+    case "none":
+        return ModeNone, nil
+    case "auto", "":
+        return autoMode(), nil
+    }
+
+    return autoMode(), ErrUnknownMode
+}
+
+// autoMode will retrieve the mode selected by the "auto" value of a
+// --progress flag.
+func autoMode() Mode {
+    if isTerminal(os.Stdout) || isTerminal(os.Stderr) {
+        return ModeTTY
+    }
+
+    return ModePlain
+}
+
+// lineMode will retrieve the line mode matching the mode.
+func (m Mode) lineMode() LineMode {
+    switch m {
+    case ModePlain:
+        return Plain
+    case ModeJSON:
+        return JSON
+    case ModeNone:
+        return Hidden
+    }
+
+    return DetectLineMode()
+}
+
+// SetMode will set the line mode of the progress bar matching the
+// specified mode. Like the line mode, it has no effect on progress
+// bars that belong to a Manager, see Manager.SetMode() instead.
+func (pb *ProgressBar) SetMode(mode Mode) {
+    pb.SetLineMode(mode.lineMode())
+}
+
+// SetMode will set how the manager displays its progress bars matching
+// the specified mode. In ModeTTY, which is the default, the manager
+// draws a row for each progress bar in place. In the other modes it
+// draws nothing itself, and each progress bar writes its own lines to
+// the writer of the manager using the matching line mode, as if it was
+// shown on its own. The mode also applies to progress bars added
+// later. While forwarding progress to a parent process, the mode has
+// no effect.
+func (m *Manager) SetMode(mode Mode) {
+    m.mu.Lock()
+    m.mode = mode
+    m.mu.Unlock()
+
+    for _, pb := range m.Bars() {
+        pb.mu.Lock()
+        pb.stopTicker()
+
+        m.mu.Lock()
+        m.applyMode(pb)
+        m.mu.Unlock()
+
+        if pb.visible && !pb.finished {
+            pb.startTicker()
+        }
+        pb.mu.Unlock()
+    }
+}
+
+// applyMode will set whether a progress bar of the manager writes its
+// own lines, and the line mode it writes them in, matching the mode of
+// the manager. The caller must hold pb.mu and m.mu.
+func (m *Manager) applyMode(pb *ProgressBar) {
+    pb.ownLines = !m.inPlace() && !m.forward
+    if pb.ownLines {
+        pb.lineMode = m.mode.lineMode()
+    }
+}
+
+// inPlace will determine whether the manager draws the rows of its
+// progress bars in place, rather than leaving each progress bar to
+// write its own lines. The caller must hold m.mu.
+func (m *Manager) inPlace() bool {
+    return !m.forward && m.mode == ModeTTY
+}
+
+// jsonLine will encode the current stats of the progress bar as a
+// line of the JSON line mode. The caller must hold pb.mu.
+func (pb *ProgressBar) jsonLine() string {
+    data, _ := json.Marshal(pb.stats())
+    return string(data)
+}
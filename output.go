@@ -0,0 +1,47 @@
+package progresscli
+
+import (
+    "io"
+    "os"
+    "sync/atomic"
+)
+
+// stderrFallbackDisabled is set to 1 by SetStderrFallback(false).
+var stderrFallbackDisabled int32
+
+// SetStderrFallback will enable or disable showing progress bars in
+// STDERR when STDOUT is redirected, see DefaultWriter(). It is enabled
+// by default.
+func SetStderrFallback(enabled bool) {
+    var disabled int32
+    if !enabled {
+        disabled = 1
+    }
+
+    atomic.StoreInt32(&stderrFallbackDisabled, disabled)
+}
+
+// DefaultWriter will retrieve the writer used by Show() and the other
+// functions that show progress bars without being given a writer. It
+// is STDOUT, unless STDOUT is redirected to a file or pipe while
+// STDERR is still a terminal, as in "tool | jq", in which case it is
+// STDERR, so that the output of the program and its progress bars
+// don't end up in the same stream. Use SetStderrFallback(false) to
+// always use STDOUT. While progress bars are forwarded to a parent
+// process, see ForwardEnv, it is always STDOUT, which is the pipe the
+// parent's ForwardAdapter reads from.
+func DefaultWriter() io.Writer {
+    if !forwarding() &&
+       atomic.LoadInt32(&stderrFallbackDisabled) == 0 &&
+       !isTerminal(os.Stdout) && isTerminal(os.Stderr) {
+        return os.Stderr
+    }
+
+    return os.Stdout
+}
+
+// isTerminal will determine whether the specified file is a terminal.
+func isTerminal(f *os.File) bool {
+    info, err := f.Stat()
+    return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
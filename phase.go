@@ -0,0 +1,184 @@
+package progresscli
+
+import (
+    "fmt"
+)
+
+// Phase represents a stage in the lifecycle of a progress bar.
+type Phase int
+
+const (
+    // NotStarted is the phase of a progress bar that has not been
+    // shown yet.
+    NotStarted Phase = iota
+
+    // Running is the phase of a progress bar that is visible and
+    // being updated.
+    Running
+
+    // Paused is the phase of a progress bar whose rendering has been
+    // paused using Pause().
+    Paused
+
+    // Finished is the phase of a progress bar that has reached its
+    // max value.
+    Finished
+
+    // Aborted is the phase of a progress bar that was stopped using
+    // Abort() before reaching its max value.
+    Aborted
+)
+
+// String will retrieve the name of the phase.
+func (p Phase) String() string {
+    switch p {
+    case NotStarted:
+        return "NotStarted"
+    case Running:
+        return "Running"
+    case Paused:
+        return "Paused"
+    case Finished:
+        return "Finished"
+    case Aborted:
+        return "Aborted"
+    }
+
+    return fmt.Sprintf("Phase(%d)", int(p))
+}
+
+// Phase will retrieve the current phase of the progress bar's
+// lifecycle.
+func (pb *ProgressBar) Phase() Phase {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    return pb.phase()
+}
+
+// phase will determine the current phase of the progress bar's
+// lifecycle. The caller must hold pb.mu.
+func (pb *ProgressBar) phase() Phase {
+    switch {
+    case pb.aborted:
+        return Aborted
+    case pb.finished:
+        return Finished
+    case pb.started.IsZero():
+        return NotStarted
+    case pb.paused:
+        return Paused
+    }
+
+    return Running
+}
+
+// Visible will report whether the progress bar is currently being
+// displayed, meaning it has been shown and has neither finished nor
+// been aborted. A paused progress bar is still visible.
+func (pb *ProgressBar) Visible() bool {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    return pb.visible && !pb.finished
+}
+
+// Pause will stop the progress bar from being redrawn. Changes made
+// while the progress bar is paused, such as calls to Increment(), are
+// still recorded and become visible once Resume() is called.
+func (pb *ProgressBar) Pause() {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    if !pb.visible || pb.finished {
+        return
+    }
+
+    pb.drawBackedOff()
+    pb.paused = true
+    pb.stopTicker()
+}
+
+// Resume will resume rendering of a paused progress bar and redraw it
+// to reflect any changes made while it was paused.
+func (pb *ProgressBar) Resume() {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    if !pb.paused {
+        return
+    }
+
+    pb.paused = false
+    pb.startTicker()
+    pb.update()
+}
+
+// Abort will stop the progress bar before it has reached its max
+// value. The current frame is left in place and the cursor is moved
+// to the next line. The progress bar will no longer be updated until
+// it is shown again. Errors writing the final line are reported on the
+// channel retrieved by Errors().
+func (pb *ProgressBar) Abort() {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    if !pb.visible || pb.finished {
+        return
+    }
+
+    pb.drawBackedOff()
+    pb.paused = false
+    pb.aborted = true
+    pb.finished = true
+    pb.stopTicker()
+    pb.autoSave(true)
+    pb.updatePrompt(true)
+    pb.notifySystemd(true)
+    pb.recordEnd()
+    pb.runCommand()
+    defer pb.unlockTerminal()
+
+    if pb.manager != nil {
+        pb.manager.mu.Lock()
+        pb.manager.ended(pb, true)
+        pb.manager.mu.Unlock()
+    }
+
+    if pb.lineMode == Forward {
+        _, err := fmt.Fprintf(pb.writer, "%s\n", pb.forwardLine())
+        pb.reportError(err)
+        return
+    }
+
+    if pb.lineMode == JSON && (pb.manager == nil || pb.ownLines) {
+        _, err := fmt.Fprintf(pb.writer, "%s\n", pb.jsonLine())
+        pb.reportError(err)
+        return
+    }
+
+    // In the append, plain and JSON line modes each frame already ends
+    // with a newline, the hidden line mode writes nothing, and the
+    // rows of a Manager are terminated by the manager itself.
+    if (pb.manager != nil && !pb.ownLines) ||
+       (pb.lineMode != Overwrite && pb.lineMode != Backspace) {
+        return
+    }
+
+    // A pinned progress bar isn't drawn on the current line, and a
+    // suspended one has already been cleared.
+    if pb.pinned() {
+        pb.unpin()
+        return
+    }
+
+    if pb.suspend.active() {
+        return
+    }
+
+    _, err := fmt.Fprint(pb.writer, "\n")
+    pb.reportError(err)
+    if pb.autoFlush {
+        flush(pb.writer)
+    }
+}
@@ -0,0 +1,88 @@
+package progresscli
+
+import (
+    "fmt"
+    "strings"
+)
+
+// SetPinnedRow will pin the progress bar to a row of the terminal, so
+// that it stays in place while the rest of the program writes normal
+// scrolling output. Row 1 is the top row, and negative rows count from
+// the bottom, -1 being the bottom row. Zero, the default, draws the
+// bar on the current line.
+//
+// Each frame is drawn at the pinned row using cursor save and restore
+// sequences, so the cursor stays where the program's own output left
+// it. If the bar is pinned to the top or bottom of the terminal, the
+// scrolling region is set to the rest of the terminal, so that output
+// scrolls without overwriting the bar. The scrolling region is reset
+// once the progress bar finishes or is aborted. Pinning only applies
+// to the Overwrite line mode and has no effect on progress bars that
+// belong to a Manager.
+func (pb *ProgressBar) SetPinnedRow(row int) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    if pb.pinned() && row == 0 && pb.visible && !pb.finished {
+        pb.unpin()
+    }
+
+    pb.pinnedRow = row
+    pb.update()
+}
+
+// pinned will determine whether the frames of the progress bar are
+// drawn at a pinned row. The caller must hold pb.mu.
+func (pb *ProgressBar) pinned() bool {
+    return pb.pinnedRow != 0 && pb.lineMode == Overwrite &&
+           pb.manager == nil
+}
+
+// pin will build the sequences drawing the rows of a frame at the
+// pinned row, leaving the cursor where it was. The caller must hold
+// pb.mu.
+func (pb *ProgressBar) pin(output string) string {
+    lines := strings.Split(output, "\n")
+    _, rows := pb.terminalSize()
+
+    top := pb.pinnedRow
+    if top < 0 {
+        top = rows + 1 + top - (len(lines) - 1)
+    }
+    if top > rows-len(lines)+1 {
+        top = rows - len(lines) + 1
+    }
+    if top < 1 {
+        top = 1
+    }
+    bottom := top + len(lines) - 1
+
+    var b strings.Builder
+    b.WriteString("\0337")
+
+    // Keep the scrolling output away from a bar at the top or bottom
+    // of the terminal. Setting the scrolling region moves the cursor,
+    // which is restored below.
+    switch {
+    case top == 1 && bottom < rows:
+        fmt.Fprintf(&b, "\033[%d;%dr", bottom+1, rows)
+    case bottom == rows && top > 1:
+        fmt.Fprintf(&b, "\033[1;%dr", top-1)
+    }
+
+    for i, line := range lines {
+        fmt.Fprintf(&b, "\033[%d;1H\033[2K%s", top+i, line)
+    }
+
+    b.WriteString("\0338")
+    return b.String()
+}
+
+// unpin will reset the scrolling region set for a pinned progress bar.
+// The caller must hold pb.mu.
+func (pb *ProgressBar) unpin() {
+    fmt.Fprint(pb.writer, "\0337\033[r\0338")
+    if pb.autoFlush {
+        flush(pb.writer)
+    }
+}
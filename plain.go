@@ -0,0 +1,129 @@
+package progresscli
+
+import (
+    "fmt"
+    "math"
+    "strings"
+    "time"
+)
+
+// DefaultPlainTemplate is the template used for the lines written in
+// the Plain line mode if none is set.
+const DefaultPlainTemplate = "{label} {percent}%{decorations}"
+
+// PlainOptions determine how often, and in what format, progress bars
+// in the Plain line mode write a line.
+type PlainOptions struct {
+    // Step is the granularity in percent at which lines are written,
+    // e.g. 10 for a line at 10%, 20%, 30% and so on. If zero, every
+    // change is written.
+    Step     float64
+
+    // Interval is the minimum time between lines. If Step is zero, a
+    // line is also written every Interval while the progress bar is
+    // stalled, so that logs show it is still running. If zero, lines
+    // are only limited by Step.
+    Interval time.Duration
+
+    // Template is the format of each line. The placeholders {label},
+    // {verb}, {percent}, {value}, {max}, {failed}, {skipped}, {rate},
+    // {elapsed}, {eta} and {decorations} are replaced with the
+    // corresponding values. If empty, DefaultPlainTemplate is used.
+    Template string
+}
+
+// SetPlainOptions will set how often, and in what format, the progress
+// bar writes a line in the Plain line mode. The first and the final
+// line are always written.
+func (pb *ProgressBar) SetPlainOptions(opts PlainOptions) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    pb.stopTicker()
+    pb.plain = opts
+    if pb.visible && !pb.finished {
+        pb.startTicker()
+    }
+}
+
+// plainLine will format a line of the Plain line mode for the state.
+// Escape sequences are removed, since plain output is meant for logs.
+func (pb *ProgressBar) plainLine(s State) string {
+    template := pb.plain.Template
+    if template == "" {
+        template = DefaultPlainTemplate
+    }
+
+    percent := fmt.Sprintf("%.0f", s.Percent)
+    if s.ShowPercentageDecimal {
+        percent = fmt.Sprintf("%.2f", s.Percent)
+    }
+    if s.Indeterminate {
+        percent = "--"
+    }
+
+    eta := "--:--"
+    if d, ok := s.ETA(); ok {
+        eta = formatDuration(d)
+    }
+
+    var decorations string
+    for _, text := range s.Decorations {
+        decorations += " " + text
+    }
+
+    line := strings.NewReplacer(
+        "{label}", s.Label,
+        "{verb}", s.Verb,
+        "{percent}", percent,
+        "{value}", formatNumber(s.Value),
+        "{max}", formatNumber(s.Max),
+        "{failed}", formatNumber(s.Failed),
+        "{skipped}", formatNumber(s.Skipped),
+        "{rate}", fmt.Sprintf("%.2f/s", s.Rate),
+        "{elapsed}", formatDuration(s.Elapsed()),
+        "{eta}", eta,
+        "{decorations}", decorations,
+    ).Replace(template)
+
+    return strings.TrimSpace(ansi_re.ReplaceAllString(line, ""))
+}
+
+// plainDue will determine whether a line should be written in the
+// Plain line mode, and if so, record that it was. The caller must hold
+// pb.mu.
+func (pb *ProgressBar) plainDue() bool {
+    percent := pb.percent()
+    now := pb.now()
+    due := pb.plainAt.IsZero() || pb.finished
+
+    if !due {
+        step, interval := pb.plain.Step, pb.plain.Interval
+        elapsed := now.Sub(pb.plainAt)
+
+        switch {
+        case interval > 0 && elapsed < interval:
+            due = false
+        case step > 0:
+            due = math.Floor(percent/step) != math.Floor(pb.plainPercent/step)
+        case interval > 0:
+            due = true
+        default:
+            due = pb.value != pb.plainValue
+        }
+    }
+
+    if due {
+        pb.plainAt = now
+        pb.plainPercent = percent
+        pb.plainValue = pb.value
+    }
+
+    return due
+}
+
+// formatNumber will format a value without trailing zeros.
+func formatNumber(v float64) string {
+    return strings.TrimSuffix(
+        strings.TrimRight(fmt.Sprintf("%.2f", v), "0"), ".")
+}
@@ -0,0 +1,279 @@
+package progresscli
+
+import (
+    "fmt"
+    "io"
+    "math"
+    "sync"
+)
+
+// Job is a unit of work run by a WorkerPool.
+type Job struct {
+    // Name is used as the label of the row displaying the job.
+    Name string
+
+    // Size is the max value of the progress bar passed to Run. If
+    // zero, the progress bar is indeterminate.
+    Size float64
+
+    // Run performs the job, reporting its progress using the specified
+    // progress bar. Returning an error records the job as failed in
+    // the overall progress bar.
+    Run  func(pb *ProgressBar) error
+}
+
+// WorkerPool runs jobs using a fixed number of workers and displays
+// their progress. The top row shows the overall number of jobs
+// completed and failed, and each worker gets a row of its own below it
+// showing the progress of its current or last job. Idle workers take
+// the next job from a shared queue, so long jobs don't hold up the
+// rest. The ETA of the top row is computed from the rate at which the
+// workers together get through the jobs, counting the part of each
+// job in progress that is done, so it stays meaningful as workers
+// pick up and finish jobs. You should initialize a new worker pool
+// using the NewWorkerPool() function.
+type WorkerPool struct {
+    manager *Manager
+    overall *ProgressBar
+    workers int
+    wg      sync.WaitGroup
+
+    // mu protects the fields below.
+    mu      sync.Mutex
+    cond    *sync.Cond
+    queue   []queuedJob
+    queued  int
+    results Results
+    started bool
+    closed  bool
+    err     error
+}
+
+// queuedJob is a job waiting in the queue of a WorkerPool, along with
+// the order in which it was submitted.
+type queuedJob struct {
+    Job
+    index int
+}
+
+// NewWorkerPool will create a new WorkerPool with the specified number
+// of workers.
+func NewWorkerPool(workers int) *WorkerPool {
+    if workers < 1 {
+        workers = 1
+    }
+
+    p := &WorkerPool{
+        manager: NewManager(),
+        overall: New(),
+        workers: workers,
+    }
+    p.cond = sync.NewCond(&p.mu)
+
+    p.overall.SetMax(0)
+    p.overall.SetZeroMax(ZeroMaxIndeterminate)
+    p.overall.AddDecorator(CountsDecorator())
+    p.overall.AddDecorator(ETADecorator())
+    p.manager.Add(p.overall)
+    return p
+}
+
+// Overall will retrieve the progress bar used for the top row, so
+// that its style and decorators can be customized.
+func (p *WorkerPool) Overall() *ProgressBar {
+    return p.overall
+}
+
+// Submit will add a job to the queue of the pool. Jobs can be
+// submitted before and while the pool is running, but not after Wait()
+// has been called.
+func (p *WorkerPool) Submit(job Job) {
+    p.mu.Lock()
+    defer p.mu.Unlock()
+
+    if p.closed {
+        return
+    }
+
+    p.queue = append(p.queue, queuedJob{job, p.queued})
+    p.queued++
+    p.grow()
+    p.cond.Signal()
+}
+
+// Show will show the pool in the writer retrieved by DefaultWriter()
+// and start its workers.
+func (p *WorkerPool) Show() {
+    p.ShowIn(DefaultWriter())
+}
+
+// ShowIn will show the pool in the specified io.Writer and start its
+// workers. Jobs submitted before the pool is shown are queued until
+// then.
+func (p *WorkerPool) ShowIn(w io.Writer) {
+    p.manager.ShowIn(w)
+
+    p.mu.Lock()
+    defer p.mu.Unlock()
+
+    p.start()
+}
+
+// Wait will wait for all submitted jobs to finish, draw the final
+// state of the pool and move the cursor below it. If the pool was
+// never shown, it is shown in io.Discard, so that the jobs run and
+// their progress is tracked for Results() and the overall progress
+// bar without being displayed. The first error returned by a job, if
+// any, is returned.
+func (p *WorkerPool) Wait() error {
+    p.mu.Lock()
+    started := p.started
+    p.mu.Unlock()
+
+    if !started {
+        p.ShowIn(io.Discard)
+    }
+
+    p.mu.Lock()
+    p.closed = true
+    p.cond.Broadcast()
+    p.mu.Unlock()
+
+    p.wg.Wait()
+
+    p.manager.Stop()
+
+    p.mu.Lock()
+    defer p.mu.Unlock()
+
+    return p.err
+}
+
+// Results will retrieve the results of the jobs that have finished so
+// far, in the order they were submitted. Call it after Wait() to get
+// the results of all jobs, for example to print a summary table using
+// WriteTable().
+func (p *WorkerPool) Results() Results {
+    p.mu.Lock()
+    defer p.mu.Unlock()
+
+    results := append(Results(nil), p.results...)
+    sortResults(results)
+    return results
+}
+
+// start will start the workers of the pool, unless they have already
+// been started. The caller must hold p.mu.
+func (p *WorkerPool) start() {
+    if p.started {
+        return
+    }
+
+    p.started = true
+    for id := 1; id <= p.workers; id++ {
+        p.wg.Add(1)
+        go p.work(id)
+    }
+}
+
+// work will run queued jobs until the queue is closed and empty. The
+// worker keeps its row from the first job it runs until it returns.
+func (p *WorkerPool) work(id int) {
+    defer p.wg.Done()
+
+    var row *ProgressBar
+    defer func() {
+        if row != nil {
+            p.manager.Remove(row)
+        }
+    }()
+
+    for {
+        p.mu.Lock()
+        for len(p.queue) == 0 && !p.closed {
+            p.cond.Wait()
+        }
+
+        if len(p.queue) == 0 {
+            p.mu.Unlock()
+            return
+        }
+
+        job := p.queue[0]
+        p.queue = p.queue[1:]
+        p.mu.Unlock()
+
+        row = p.run(id, row, job.Job, job.index)
+    }
+}
+
+// run will run a single job in the row of the worker, replacing the
+// progress bar of its previous job, if any, and return the progress
+// bar displaying the job.
+func (p *WorkerPool) run(id int, row *ProgressBar, job Job, index int) *ProgressBar {
+    pb := New()
+    pb.SetVerb(fmt.Sprintf("#%d", id))
+    pb.SetLabel(job.Name)
+    pb.SetZeroMax(ZeroMaxIndeterminate)
+    pb.SetMax(job.Size)
+
+    // Each job costs one unit of work in the overall progress bar, of
+    // which the part already done is added as the job progresses.
+    var done float64
+    if job.Size > 0 {
+        pb.progressHook = func(value float64) {
+            fraction := math.Min(value/job.Size, 1)
+            p.overall.addCost(fraction - done)
+            done = fraction
+        }
+    }
+
+    if row == nil {
+        p.manager.Add(pb)
+    } else {
+        p.manager.replace(row, pb)
+    }
+
+    result := runJob(pb, job, index)
+    p.overall.addCost(1 - done)
+    if result.Err != nil {
+        pb.Abort()
+    } else {
+        pb.mu.Lock()
+        pb.finish()
+        pb.mu.Unlock()
+    }
+
+    p.mu.Lock()
+    p.results = append(p.results, result)
+    if result.Err != nil && p.err == nil {
+        p.err = result.Err
+    }
+    p.mu.Unlock()
+
+    if result.Err != nil {
+        p.overall.IncrementFailed(1)
+        return pb
+    }
+
+    p.overall.Increment(1)
+    return pb
+}
+
+// grow will add a job to the max value of the overall progress bar,
+// restarting it if it had already finished the previous jobs. The
+// caller must hold p.mu.
+func (p *WorkerPool) grow() {
+    pb := p.overall
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    pb.max++
+    pb.totalCost = pb.max
+    if pb.finished && pb.visible {
+        pb.finished = false
+        pb.startTicker()
+    }
+
+    pb.update()
+}
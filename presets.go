@@ -0,0 +1,141 @@
+package progresscli
+
+import (
+    "sort"
+)
+
+// presets maps the names accepted by StyleByName() to the functions
+// producing each style. Only the styles using colors that are hard to
+// read on a light background have a "-light" variant; the others are
+// either colorless or readable on both.
+var presets = map[string]func() Style{
+    "default":         DefaultStyle,
+    "default-nocolor": DefaultStyleNoColor,
+    "default-light":   DefaultStyleLight,
+    "line":            LineStyle,
+    "line-nocolor":    LineStyleNoColor,
+    "line-light":      LineStyleLight,
+    "minimal":         MinimalStyle,
+    "minimal-16":      MinimalStyle16,
+    "minimal-light":   MinimalStyleLight,
+    "pacman":          PacmanStyle,
+    "apt":             AptStyle,
+    "yarn":            YarnStyle,
+    "npm":             NpmStyle,
+    "cargo":           CargoStyle,
+}
+
+// StyleByName will retrieve one of the built in styles by name. If no
+// style exists with the specified name, false is returned. Use
+// StyleNames() to list the available names.
+func StyleByName(name string) (Style, bool) {
+    preset, ok := presets[name]
+    if !ok {
+        return Style{}, false
+    }
+
+    return preset(), true
+}
+
+// StyleNames will retrieve the names of all built in styles in
+// alphabetical order.
+func StyleNames() []string {
+    names := make([]string, 0, len(presets))
+    for name := range presets {
+        names = append(names, name)
+    }
+
+    sort.Strings(names)
+    return names
+}
+
+// PacmanStyle will retrieve a Style resembling the progress bars of
+// the pacman package manager, e.g. [#####-----].
+func PacmanStyle() Style {
+    return Style {
+        OpenChar: "[",
+        CloseChar: "]",
+        DoneChar: "#",
+        NotDoneChar: "-",
+        InProgressChar: "",
+    }
+}
+
+// AptStyle will retrieve a Style resembling the progress bar apt
+// displays at the bottom of the terminal, e.g. [#####.....].
+func AptStyle() Style {
+    return Style {
+        OpenChar: "[",
+        CloseChar: "]",
+        DoneChar: "#",
+        NotDoneChar: ".",
+        InProgressChar: "",
+    }
+}
+
+// YarnStyle will retrieve a Style resembling the progress bars of
+// yarn, including its spinner at the leading edge of the bar.
+func YarnStyle() Style {
+    return Style {
+        OpenChar: "[",
+        CloseChar: "]",
+        DoneChar: "#",
+        NotDoneChar: "-",
+        InProgressChar: "⠁",
+        Spinner: []string{"⠁", "⠂", "⠄", "⡀", "⢀", "⠠", "⠐", "⠈"},
+    }
+}
+
+// NpmStyle will retrieve a Style resembling the progress gauge of
+// npm, including its spinner at the leading edge of the bar.
+func NpmStyle() Style {
+    return Style {
+        OpenChar: "⸨",
+        CloseChar: "⸩",
+        DoneChar: "█",
+        NotDoneChar: "░",
+        InProgressChar: "⠋",
+        Spinner: []string{
+            "⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏",
+        },
+    }
+}
+
+// CargoStyle will retrieve a Style resembling the progress bar of
+// cargo. It is intended to be used together with SetVerb(), which
+// displays the verb bold and right-aligned in a 12 column wide
+// field, e.g. "    Building [=====>     ]".
+func CargoStyle() Style {
+    return Style {
+        OpenChar: "[",
+        CloseChar: "]",
+        DoneChar: "=",
+        NotDoneChar: " ",
+        InProgressChar: ">",
+        VerbColor: "\033[1;36m",
+        VerbWidth: 12,
+    }
+}
+
+// MinimalStyle will retrieve a Style drawing the progress bar as a
+// cyan band over a dim gray track, without any characters other than
+// spaces.
+func MinimalStyle() Style {
+    return Style {
+        DoneChar: "\033[46m \033[0m",
+        InProgressChar: "",
+        TrackColor: "\033[48;5;237m",
+    }
+}
+
+// MinimalStyle16 will retrieve a variant of MinimalStyle() that only
+// uses the 8 basic ANSI colors, for terminals such as the Linux
+// console that lack the 256 color palette. The band is drawn over a
+// blue track.
+func MinimalStyle16() Style {
+    return Style {
+        DoneChar: "\033[46m \033[0m",
+        InProgressChar: "",
+        TrackColor: "\033[44m",
+    }
+}
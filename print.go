@@ -0,0 +1,108 @@
+package progresscli
+
+import (
+    "fmt"
+    "io"
+)
+
+// Println will print a line of text, formatted as by fmt.Println,
+// above the progress bar. The progress bar is cleared, the line is
+// written in its place and the progress bar is drawn again below it,
+// so that log output and a live progress bar can share the terminal.
+// If the progress bar belongs to a Manager, the line is printed above
+// all of the manager's progress bars.
+func (pb *ProgressBar) Println(a ...any) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    if pb.manager != nil {
+        pb.manager.Println(a...)
+        return
+    }
+
+    text := fmt.Sprintln(a...)
+    w := printWriter(pb.writer)
+    if pb.visible && !pb.finished && pb.lineMode == Backspace &&
+       !pb.suspend.active() {
+        fmt.Fprint(w, pb.backspaceClear()+text)
+        pb.draw()
+        return
+    }
+
+    if !pb.visible || pb.finished || pb.lineMode != Overwrite ||
+       pb.pinned() || pb.suspend.active() {
+        fmt.Fprint(w, text)
+        if pb.autoFlush {
+            flush(w)
+        }
+        return
+    }
+
+    if pb.drawnRows > 1 {
+        fmt.Fprintf(w, "\033[%dA", pb.drawnRows-1)
+    }
+
+    fmt.Fprint(w, "\r\033[J"+text)
+    pb.drawnRows = 0
+    pb.draw()
+}
+
+// Println will print a line of text, formatted as by fmt.Println,
+// above the progress bars of the manager. The progress bars are
+// cleared, the line is written in their place and the progress bars
+// are drawn again below it.
+func (m *Manager) Println(a ...any) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    m.print(nil, fmt.Sprintln(a...))
+}
+
+// print will write text above the progress bars of the manager, to the
+// specified writer, or to the writer of the manager if it is nil. The
+// progress bars are cleared first and drawn again below the text. The
+// caller must hold m.mu.
+func (m *Manager) print(out io.Writer, text string) {
+    w := printWriter(m.writer)
+    own := out == nil
+    if own {
+        out = w
+    }
+
+    if !m.visible || !m.inPlace() || m.suspend.active() {
+        fmt.Fprint(out, text)
+        if m.autoFlush {
+            flush(out)
+        }
+        return
+    }
+
+    m.buf.Reset()
+    if m.lines > 1 {
+        fmt.Fprintf(&m.buf, "\033[%dA", m.lines-1)
+    }
+
+    m.buf.WriteString("\r\033[J")
+    if own {
+        m.buf.WriteString(text)
+        w.Write(m.buf.Bytes())
+    } else {
+        w.Write(m.buf.Bytes())
+        fmt.Fprint(out, text)
+    }
+
+    m.lines = 0
+    m.drawn = nil
+    m.draw()
+}
+
+// printWriter will retrieve the writer lines are printed to: the
+// writer a progress bar or manager was shown in, or the one retrieved
+// by DefaultWriter() if it hasn't been shown yet.
+func printWriter(w io.Writer) io.Writer {
+    if w == nil {
+        return DefaultWriter()
+    }
+
+    return w
+}
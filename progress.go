@@ -1,16 +1,37 @@
 package progresscli
 
 import (
+    "errors"
     "os"
     "io"
-    "fmt"
-    "unicode/utf8"
     "math"
+    "reflect"
     "regexp"
-
-    "github.com/nathan-fiscaletti/consolesize-go"
+    "strings"
+    "sync"
+    "time"
 )
 
+// ErrAlreadyVisible is returned when showing a progress bar that is
+// already visible in a different io.Writer.
+var ErrAlreadyVisible = errors.New(
+    "progresscli: progress bar is already visible in another writer")
+
+// ErrDuplicateName is returned when adding a progress bar to a Manager
+// under a name that is already in use.
+var ErrDuplicateName = errors.New(
+    "progresscli: a progress bar with this name has already been added")
+
+// ErrInvalidMax is returned when setting a max value that is
+// negative, NaN or infinite.
+var ErrInvalidMax = errors.New(
+    "progresscli: max value must be a finite, non-negative number")
+
+// ErrManaged is returned when showing a progress bar individually
+// that belongs to a Manager.
+var ErrManaged = errors.New(
+    "progresscli: progress bar is managed by a Manager")
+
 // Style represents the style that can be applied to a progress bar.
 type Style struct {
     // The open and close characters are the characters on either end
@@ -19,6 +40,12 @@ type Style struct {
     OpenChar        string
     CloseChar       string
 
+    // The finished open and close characters optionally replace the
+    // open and close characters once the progress bar is complete, for
+    // example to turn the brackets green.
+    FinishedOpenChar  string
+    FinishedCloseChar string
+
     // The done character is the character used to represent a
     // completed section of the progress bar.
     DoneChar        string
@@ -31,47 +58,393 @@ type Style struct {
     // section of the progress bar that is currently in progress.
     InProgressChar  string
 
+    // The failed character is an optional character used to represent
+    // the items recorded using IncrementFailed(). The failed items are
+    // displayed at the end of the completed section. If it is empty,
+    // failed items are displayed using the done character.
+    FailedChar      string
+
+    // The skipped character is an optional character used to
+    // represent the items recorded using Skip(). The skipped items are
+    // displayed at the start of the completed section. If it is empty,
+    // skipped items are displayed using the done character.
+    SkippedChar     string
+
+    // The track color is an optional escape sequence setting a
+    // background color, usually a dim one. If it is set, the section
+    // of the progress bar that has not yet been completed is drawn as
+    // a band of spaces in that color instead of using the not-done
+    // character, for a minimal look. Combine it with a done character
+    // that also uses a background color, e.g. "\033[46m \033[0m".
+    TrackColor      string
+
+    // The marker character is an optional glyph drawn over the section
+    // of the progress bar that has not yet been completed at every
+    // marker interval percent of the bar, e.g. every 10%. Occurrences
+    // of "%d" in it are replaced with the percentage of the marker.
+    // See SetDistanceMarkers().
+    MarkerChar      string
+    MarkerInterval  float64
+
+    // The padding is placed between the open and close characters and
+    // the bar, on both sides.
+    Padding         string
+
+    // The spinner is an optional sequence of frames used in place of
+    // the in-progress character. Each time the progress bar is
+    // rendered, the next frame is displayed. All frames should have
+    // the same width.
+    Spinner         []string
+
     // The percentage color is the text that can be placed immediately
     // before the percentage print out and is most commonly used for
     // ANSI escape sequences to change the color of the text.
     PercentageColor string
+
+    // The verb color is the text placed immediately before the verb
+    // set with SetVerb() and is most commonly used for ANSI escape
+    // sequences to change the color of the text.
+    VerbColor       string
+
+    // The verb width is the width of the column the verb is
+    // right-aligned in. Keeping the width constant lines up the bars
+    // of consecutive steps, such as "   Compiling" and " Downloading".
+    VerbWidth       int
 }
 
 // ProgressBar represents an instance of a Progress Bar. You should
 // initialize a new progress-bar using the New() or NewWithStyle()
-// functions.
+// functions. A ProgressBar is safe for concurrent use.
 type ProgressBar struct {
+    mu                    sync.Mutex
     style                 Style
+    styleWidths           StyleWidths
+    drawn                 drawnStyle
     max                   float64
     showPercentage        bool
     showPercentageDecimal bool
     label                 string
     showLabel             bool
+    verb                  string
     writer                io.Writer
     value                 float64
+    failed                float64
+    skipped               float64
+    skipReasons           []skipReason
     maxWidth              int
     useCustomMaxWidth     bool
     finished              bool
     visible               bool
+    paused                bool
+    aborted               bool
+    lineMode              LineMode
+    forwardID             int64
+    plain                 PlainOptions
+    plainAt               time.Time
+    plainPercent          float64
+    plainValue            float64
+    zeroMax               ZeroMax
+    direction             Direction
+    decorators            []decoratorEntry
+    renderer              Renderer
+    frameFilter           func(frame string) string
+    manager               *Manager
+    started               time.Time
+    history               *sampleRing
+    clock                 Clock
+    frame                 int
+    refreshInterval       time.Duration
+    lastDraw              time.Time
+    adaptive              bool
+    easing                time.Duration
+    easeFrom              float64
+    easeTo                float64
+    easeStart             time.Time
+    rows                  int
+    drawnRows             int
+    drawnWidth            int
+    status                bool
+    touched               time.Time
+    counter               bool
+    blipDrawn             bool
+    stopped               bool
+    gauge                 func() int
+    colors                ColorProvider
+    dryRun                bool
+    smoothing             Smoothing
+    smoothed              float64
+    labelWidth            int
+    prefix                string
+    suffix                string
+    renderStats           RenderStats
+    sizes                 SizeProvider
+    resized               <-chan struct{}
+    resizeDone            chan struct{}
+    pinnedRow             int
+    suspend               suspension
+    terminalLock          bool
+    lockFile              *os.File
+    lockHeld              bool
+    lockDone              chan struct{}
+    colorLevel            ColorLevel
+    scale                 Scale
+    cost                  float64
+    totalCost             float64
+    warmup                Warmup
+    promptPath            string
+    promptText            string
+    promptWriter          promptWriter
+    recorded              *recordedTask
+    systemdNotify         bool
+    systemdStatus         string
+    systemdExtend         time.Duration
+    errors                chan error
+    decimalsAbove         float64
+    items                 []string
+    timeLocation          *time.Location
+    timeFormat            string
+    minBarWidth           int
+    progressHook          func(value float64)
+    lastFrame             []byte
+    scheduler             Scheduler
+    drawScheduled         bool
+    autoFlush             bool
+    latency               writeLatency
+    ownLines              bool
+    backedOff             bool
+    tickerDone            chan struct{}
+    autoSaveStore         StateStore
+    autoSaveKey           string
+    autoSaveInterval      time.Duration
+    lastAutoSave          time.Time
+    autoSaver             autoSaver
+    finishCommand         []string
+    failureCommand        []string
+    group                 *Group
+    ownStyle              bool
+    groupDecorators       []decoratorEntry
+    hideGroupDecorators   bool
+}
+
+// Decorator produces a piece of text that is displayed to the right
+// of a progress bar, after the percentage. Decorators are called each
+// time the progress bar is rendered with a snapshot of its state, and
+// the space they take up is subtracted from the space available to
+// the bar itself.
+type Decorator func(s State) string
+
+// decoratorEntry is a decorator added to a progress bar. Decorators
+// with an interval keep the text they last produced until the interval
+// has passed.
+type decoratorEntry struct {
+    decorate Decorator
+    interval time.Duration
+    text     string
+    at       time.Time
+}
+
+// AddDecorator will append a decorator to the progress bar.
+// Decorators are displayed in the order they were added.
+func (pb *ProgressBar) AddDecorator(d Decorator) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    pb.decorators = append(pb.decorators, decoratorEntry{decorate: d})
+    pb.update()
+}
+
+// AddTimedDecorator will append a decorator that is refreshed on its
+// own interval, such as an ElapsedDecorator() refreshed every second.
+// The progress bar is redrawn whenever the interval passes, even if
+// nothing else changed, while in between its text is reused when the
+// progress bar is redrawn because its value changed. This keeps time
+// based decorators moving without redrawing an idle progress bar more
+// often than they need.
+func (pb *ProgressBar) AddTimedDecorator(d Decorator, interval time.Duration) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    pb.stopTicker()
+    pb.decorators = append(pb.decorators, decoratorEntry{
+        decorate: d,
+        interval: interval,
+    })
+    if pb.visible && !pb.finished {
+        pb.startTicker()
+    }
+
+    pb.update()
+}
+
+// decorate will call the decorators of the progress bar and return
+// the non-empty text they produced. If cached is true, decorators with
+// an interval reuse the text they last produced until their interval
+// has passed. In dry run mode, the amount of planned work is returned
+// instead. The caller must hold pb.mu.
+func (pb *ProgressBar) decorate(s State, cached bool) []string {
+    if s.DryRun {
+        return []string{plannedDecoration(s)}
+    }
+
+    // The decorators of the progress bar's group come first.
+    lists := [][]decoratorEntry{pb.decorators}
+    if !pb.hideGroupDecorators {
+        lists = [][]decoratorEntry{pb.groupDecorators, pb.decorators}
+    }
+
+    var decorations []string
+    for _, entries := range lists {
+        for i := range entries {
+            d := &entries[i]
+
+            var text string
+            if !cached || d.interval <= 0 {
+                text = d.decorate(s)
+            } else {
+                if d.at.IsZero() || pb.since(d.at) >= d.interval {
+                    d.text = d.decorate(s)
+                    d.at = pb.now()
+                }
+                text = d.text
+            }
+
+            if strLen(text) > 0 {
+                decorations = append(decorations, text)
+            }
+        }
+    }
+
+    return decorations
 }
 
 // SetLabel sets the label for the progress bar. The label will be
 // displayed on the left side of the progress bar.
 func (pb *ProgressBar) SetLabel(label string) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
     pb.label = label
     pb.showLabel = strLen(label) > 0
-    if pb.visible {
-        pb.Increment(0)
+    pb.update()
+}
+
+// SetLabelWidth will reserve a column of the specified width for the
+// label. Shorter labels are padded with spaces and longer ones are
+// truncated with an ellipsis, so that the bars of consecutive steps
+// with labels of different lengths start at the same column. The
+// column is reserved even if no label is set. A width of zero, the
+// default, sizes the label to fit.
+func (pb *ProgressBar) SetLabelWidth(width int) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    if width < 0 {
+        width = 0
+    }
+
+    pb.labelWidth = width
+    pb.update()
+}
+
+// SetMinBarWidth will guarantee that the section between the open and
+// close characters keeps at least the specified number of columns
+// when the terminal is too narrow for the whole frame. Decorations
+// are dropped, starting with the last one, or shortened to make room
+// for it, and the bar only falls back to the compact text layout if
+// the verb, label and percentage alone leave no room for it. Zero,
+// the default, falls back as soon as the frame doesn't fit.
+func (pb *ProgressBar) SetMinBarWidth(width int) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    if width < 0 {
+        width = 0
+    }
+
+    pb.minBarWidth = width
+    pb.update()
+}
+
+// SetVerb sets the verb for the progress bar. The verb is displayed
+// right-aligned in a fixed width column before the label, using the
+// VerbColor and VerbWidth of the progress bar's style. An empty verb
+// removes the column.
+func (pb *ProgressBar) SetVerb(verb string) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    pb.verb = verb
+    pb.update()
+}
+
+// LineMode determines how consecutive frames of a progress bar are
+// written to its writer.
+type LineMode int
+
+const (
+    // Overwrite writes each frame over the previous one using a
+    // carriage return. This is the default line mode.
+    Overwrite LineMode = iota
+
+    // Append writes each frame on a new line without clearing the
+    // previous one. This is useful for debug logging, screen readers
+    // and terminals or recorders that mangle carriage returns.
+    Append
+
+    // Plain writes a line of plain text, without the bar itself or any
+    // escape sequences, at the granularity set with SetPlainOptions().
+    // This is useful for CI logs, where every frame of the bar would
+    // flood the log.
+    Plain
+
+    // Forward writes the state of the progress bar as lines of a
+    // protocol understood by a ForwardAdapter in a parent process,
+    // which renders the progress bar on its behalf. It is used
+    // automatically if ForwardEnv is set, and applies to progress bars
+    // that belong to a Manager as well.
+    Forward
+
+    // Backspace writes each frame over the previous one by moving back
+    // over it using backspaces, for consoles that ignore carriage
+    // returns, such as the output panes of some IDEs. Frames wrapping
+    // across several rows are drawn on a single line. See
+    // DetectLineMode().
+    Backspace
+
+    // JSON writes the stats of the progress bar, as returned by
+    // Stats(), as one JSON object per line, for tools consuming the
+    // progress of a job programmatically. Lines are written at the
+    // granularity set with SetPlainOptions(), and a final line is
+    // written when the progress bar is aborted.
+    JSON
+
+    // Hidden doesn't write anything, for users asking for no progress
+    // output at all. Lines printed using Println() are still written.
+    Hidden
+)
+
+// SetLineMode will set the line mode used when writing frames of the
+// progress bar. The line mode has no effect on progress bars that
+// belong to a Manager.
+func (pb *ProgressBar) SetLineMode(mode LineMode) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    pb.stopTicker()
+    pb.lineMode = mode
+    if pb.visible && !pb.finished {
+        pb.startTicker()
     }
 }
 
 // SetShowPercentage will tell the progress bar to either display the
 // current percentage or not to display it.
 func (pb *ProgressBar) SetShowPercentage(show bool) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
     pb.showPercentage = show
-    if pb.visible {
-        pb.Increment(0)
-    }
+    pb.update()
 }
 
 // SetShowPercentageDecimal will tell the progress bar to display the
@@ -79,99 +452,201 @@ func (pb *ProgressBar) SetShowPercentage(show bool) {
 // function will automatically force the percentage to be displayed,
 // so it is not required that you also call SetShowPercentage(true).
 func (pb *ProgressBar) SetShowPercentageDecimal(show bool) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
     if show {
         pb.showPercentage = true
     }
 
     pb.showPercentageDecimal = show
-    if pb.visible {
-        pb.Increment(0)
-    }
+    pb.update()
 }
 
 // SetMax will set the maximum value for the progress bar. The default
-// maximum value is 100.
-func (pb *ProgressBar) SetMax(max float64) {
-    pb.max = max
-    if pb.visible {
-        pb.Increment(0)
+// maximum value is 100. A max value of zero is allowed, see
+// SetZeroMax() for how it is displayed. If max is negative, NaN or
+// infinite, ErrInvalidMax is returned and the max value is left
+// unchanged.
+func (pb *ProgressBar) SetMax(max float64) error {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    if !validMax(max) {
+        return ErrInvalidMax
     }
+
+    pb.max = max
+    pb.update()
+    return nil
+}
+
+// validMax will determine whether max is a valid max value, which is
+// finite and not negative.
+func validMax(max float64) bool {
+    return max >= 0 && !math.IsNaN(max) && !math.IsInf(max, 0)
 }
 
 // GetMax will retrieve the current max value for the progress bar.
 func (pb *ProgressBar) GetMax() float64 {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
     return pb.max
 }
 
 // SetMaxWidth will set the maximum width for the progress bar in 
 // columns. The default value is the current width of the console.
 func (pb *ProgressBar) SetMaxWidth(maxWidth int) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
     pb.maxWidth = maxWidth
     pb.useCustomMaxWidth = true
-    if pb.visible {
-        pb.Increment(0)
-    }
+    pb.update()
 }
 
 // UseFullWidth will set the progress bar to use the current width in
 // columns of the open console window. This is the default setting.
 func (pb *ProgressBar) UseFullWidth() {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
     pb.maxWidth = 0
     pb.useCustomMaxWidth = false
-    if pb.visible {
-        pb.Increment(0)
-    }
+    pb.update()
 }
 
 // GetMaxWidth will retrieve the current maximum width of the
 // progress bar in columns. If no custom maximum width has been set,
 // the current width of the open console window will be returned.
 func (pb *ProgressBar) GetMaxWidth() int {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
     if pb.useCustomMaxWidth {
         return pb.maxWidth
     }
 
-    cols, _ := consolesize.GetConsoleSize()
+    cols, _ := pb.terminalSize()
     return cols
 }
 
 // GetValue will retrieve the current value of the progress bar.
 func (pb *ProgressBar) GetValue() float64 {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
     return pb.value
 }
 
 // SetValue will set the current value of the progress bar.
 func (pb *ProgressBar) SetValue(value float64) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
     pb.value = value
-    if pb.visible {
-        pb.Increment(0)
-    }
+    pb.update()
 }
 
-// Show will show the progress bar in STDOUT.
-func (pb *ProgressBar) Show() {
-    pb.ShowIn(os.Stdout)
+// Show will show the progress bar in STDOUT, or in STDERR if STDOUT
+// is redirected, see DefaultWriter(). See ShowIn() for the behavior
+// when the progress bar is already visible.
+func (pb *ProgressBar) Show() error {
+    return pb.ShowIn(DefaultWriter())
 }
 
-// ShowIn will show the progress bar in the specified io.Writer
-func (pb *ProgressBar) ShowIn(w io.Writer) {
+// ShowIn will show the progress bar in the specified io.Writer.
+//
+// Showing a progress bar does not reset its value, so a value set
+// before the progress bar is shown is kept. If the progress bar has
+// already finished, showing it again starts over from zero, or from
+// its max value when counting down, on a new line. If the progress
+// bar is currently visible in the same writer, it is simply redrawn.
+// If it is currently visible in a different writer, ErrAlreadyVisible
+// is returned and nothing is written. Progress bars belonging to a
+// Manager cannot be shown individually and ErrManaged is returned for
+// them.
+func (pb *ProgressBar) ShowIn(w io.Writer) error {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    if pb.manager != nil {
+        return ErrManaged
+    }
+
+    if pb.visible && !pb.finished {
+        if !sameWriter(pb.writer, w) {
+            return ErrAlreadyVisible
+        }
+
+        pb.draw()
+        return nil
+    }
+
+    if pb.finished {
+        pb.reset()
+    }
+
     pb.visible = true
     pb.writer = w
-    pb.finished = false
+    pb.begin()
+    return nil
+}
+
+// reset will move the progress bar back to its start and clear its
+// counters, so that it can run again. The caller must hold pb.mu.
+func (pb *ProgressBar) reset() {
     pb.value = 0
-    pb.Increment(0)
+    if pb.direction == Down {
+        pb.value = pb.max
+    }
+    pb.failed = 0
+    pb.skipped = 0
+    pb.skipReasons = nil
+    pb.stopped = false
+    pb.cost = 0
+}
+
+// begin will start running a visible progress bar, or run it again
+// if it has finished, restarting its ticker and drawing it. The
+// caller must hold pb.mu.
+func (pb *ProgressBar) begin() {
+    pb.finished = false
+    pb.drawnRows = 0
+    pb.drawnWidth = 0
+    pb.paused = false
+    pb.aborted = false
+    pb.start()
+    pb.lockTerminal()
+    pb.startTicker()
+    pb.update()
 }
 
 // Increment will increment the progress bar by the specified count.
 // The value of the progress bar will be constrained to 0-max where
 // max is the current max value for the progress bar.
 func (pb *ProgressBar) Increment(count float64) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
     if pb.finished || !pb.visible {
         return
     }
 
     pb.value += count
-    if pb.value > pb.max {
+    pb.update()
+}
+
+// update will constrain the value of the progress bar and redraw it,
+// unless the progress bar is not visible, paused or throttled by its
+// refresh interval. The caller must hold pb.mu.
+func (pb *ProgressBar) update() {
+    if pb.finished || !pb.visible {
+        return
+    }
+
+    if pb.value > pb.max && !pb.indeterminate() {
         pb.value = pb.max
     }
 
@@ -179,144 +654,253 @@ func (pb *ProgressBar) Increment(count float64) {
         pb.value = 0
     }
 
-    var output                   string
-    var percent                  float64
-    var labelLength              int
-    var labelSpacerLength        int
-    var percentLabel             string
-    var percentLabelLength       int
-    var percentLabelSpacerLength int
+    if pb.progressHook != nil {
+        pb.progressHook(pb.value)
+    }
 
-    var progressBarAvailableLength int
-    var progressBarMinimumLength   int
-    var labelsLength               int
+    pb.autoSave(false)
+    pb.updatePrompt(false)
+    pb.notifySystemd(false)
+    pb.recordProgress()
+    pb.smooth()
+    pb.retarget()
 
-    percent = (pb.value / pb.max) * 100.0;
-    if !pb.showPercentageDecimal {
-        percent = math.Trunc(percent)
+    if pb.paused || pb.suspend.active() {
+        return
     }
 
-    if pb.showLabel {
-        labelLength = strLen(pb.label)
-        labelSpacerLength = 1
+    // With a refresh interval, frames are drawn by the ticker and only
+    // the final frame is drawn right away.
+    if pb.refreshInterval > 0 && !pb.complete() &&
+       pb.since(pb.lastDraw) < pb.refreshInterval {
+        pb.renderStats.Dropped++
+        return
     }
 
-    if pb.showPercentage {
-        if pb.showPercentageDecimal {
-            percentLabel = fmt.Sprintf("%.2f%%", percent)
-            percentLabelLength = strLen(fmt.Sprintf("%.2f%%", 100.0))
-        } else {
-            percentLabel = fmt.Sprintf("%.0f%%", percent)
-            percentLabelLength = strLen(fmt.Sprintf("%.0f%%", 100.0))
+    if pb.backoff() {
+        pb.renderStats.Dropped++
+        return
+    }
+
+    pb.requestDraw()
+}
+
+// percent will compute the percentage that should be displayed for
+// the current value of the progress bar.
+func (pb *ProgressBar) percent() float64 {
+    return pb.percentOf(pb.value)
+}
+
+// percentOf will compute the percentage that should be displayed for
+// the specified value of the progress bar.
+func (pb *ProgressBar) percentOf(value float64) float64 {
+    if pb.max == 0 {
+        if pb.indeterminate() {
+            return 0
         }
 
-        percentLabelSpacerLength = 1
+        return 100
     }
 
-    if pb.showPercentage {
-        labelsLength += percentLabelLength + percentLabelSpacerLength
+    percent := (value / pb.max) * 100.0
+    if !pb.showPercentageDecimal {
+        percent = math.Trunc(percent)
     }
 
-    if pb.showLabel {
-        labelsLength += labelLength + labelSpacerLength
-    }
+    return percent
+}
 
-    progressBarMinimumLength = strLen(pb.style.DoneChar) + 
-                               strLen(pb.style.NotDoneChar) + 
-                               strLen(pb.style.InProgressChar)
-    cols, _ := consolesize.GetConsoleSize()
-    if pb.useCustomMaxWidth { 
-        progressBarAvailableLength = pb.maxWidth - 
-                                     labelsLength - 
-                                     strLen(pb.style.CloseChar) - 
-                                     strLen(pb.style.OpenChar)
-    } else {
-        progressBarAvailableLength = cols - 
-                                     labelsLength - 
-                                     strLen(pb.style.CloseChar) - 
-                                     strLen(pb.style.OpenChar)
+// draw will write the current frame of the progress bar to its
+// writer, replacing the previous frame. If the progress bar belongs
+// to a Manager, the manager redraws all of its progress bars instead.
+// The caller must hold pb.mu.
+func (pb *ProgressBar) draw() {
+    output, err := pb.render()
+    if err != nil {
+        pb.reportError(err)
+        return
     }
 
-    // Clear the line before writing to it
-    output += "\r"
-    for i := 0; i<cols; i++ {
-        output += " "
+    pb.finished = pb.complete()
+    pb.lastDraw = pb.now()
+    if pb.finished {
+        pb.stopTicker()
+        pb.autoSave(true)
+        pb.updatePrompt(true)
+        pb.notifySystemd(true)
+        pb.recordEnd()
+        pb.runCommand()
     }
-    output += "\r"
 
-    if progressBarAvailableLength < progressBarMinimumLength {
-        if pb.showLabel && pb.showPercentage {
-            output += fmt.Sprintf("%s %s", pb.label, percentLabel)
-        } else if pb.showPercentage {
-            output += fmt.Sprintf("%s", percentLabel)
-        } else {
-            output += fmt.Sprintf("%s", "Loading...")
-        }
-    } else {
-        if pb.showLabel {
-            output += fmt.Sprintf("%s ", pb.label)
+    if pb.manager != nil {
+        if pb.lineMode != Forward && !pb.ownLines {
+            pb.renderStats.BytesWritten += int64(len(output))
+            pb.lastFrame = append(pb.lastFrame[:0], output...)
+            pb.manager.update(pb, output)
+            return
         }
 
-        output += fmt.Sprintf("%s", pb.style.OpenChar)
+        pb.manager.mu.Lock()
+        pb.manager.ended(pb, pb.finished)
+        pb.manager.mu.Unlock()
+    }
 
-        var progressFillSize int
-        progressFillSize = progressBarAvailableLength - 
-                           strLen(pb.style.InProgressChar)
-        filledBarLength := int(math.Trunc((percent / 100) * 
-                               float64(progressFillSize)))
+    if pb.finished {
+        defer pb.unlockTerminal()
+    }
 
-        if filledBarLength > 0 {
-            for i := 0; i < filledBarLength; i++ {
-                output += fmt.Sprintf("%s", pb.style.DoneChar)
-            }
-        }
+    pb.backedOff = false
+    start := pb.now()
+    defer func() {
+        pb.latency.observe(start, pb.now())
+    }()
+    if pb.autoFlush {
+        defer flush(pb.writer)
+    }
 
-        if strLen(pb.style.InProgressChar) > 0 {
-            if percent < 100 {
-                output += fmt.Sprintf("%s", pb.style.InProgressChar)
-            } else {
-                output += fmt.Sprintf("%s", pb.style.DoneChar)
+    var n int
+    defer func() {
+        pb.renderStats.BytesWritten += int64(n)
+        pb.reportError(err)
+    }()
+
+    if pb.lineMode == Hidden {
+        return
+    }
+
+    if pb.lineMode == Plain || pb.lineMode == JSON {
+        if pb.plainDue() {
+            if pb.lineMode == JSON {
+                output = pb.jsonLine()
             }
+            n, err = pb.writeFrame(output + "\n")
+        } else {
+            pb.renderStats.Dropped++
         }
+        return
+    }
 
-        for j := 0; j < progressBarAvailableLength -
-                        filledBarLength -
-                        strLen(pb.style.InProgressChar); j++ {
-            output += fmt.Sprintf("%s", pb.style.NotDoneChar)
-        }
+    if pb.lineMode == Append || pb.lineMode == Forward {
+        n, err = pb.writeFrame(output + "\n")
+        return
+    }
 
-        if strLen(pb.style.CloseChar) > 0 {
-            output += fmt.Sprintf("%s", pb.style.CloseChar)
+    if pb.lineMode == Backspace {
+        output = pb.backspaceFrame(output)
+        if pb.finished {
+            output += "\n"
         }
+        n, err = pb.writeFrame(output)
+        return
+    }
 
-        if pb.showPercentage {
-            output += fmt.Sprintf(
-                " %s%4s", pb.style.PercentageColor, percentLabel)
+    if pb.pinned() {
+        n, err = pb.writeFrame(pb.pin(output))
+        if pb.finished {
+            pb.unpin()
         }
+        return
+    }
+
+    // Clear the line before writing to it
+    cols, _ := pb.terminalSize()
+    clear := "\r" + strings.Repeat(" ", cols) + "\r"
+
+    output = pb.overwriteRows(output, clear)
+    if pb.finished {
+        output += "\n"
     }
+    n, err = pb.writeFrame(output)
+}
+
+// render will build a single frame of the progress bar using its
+// renderer, without any of the sequences used to clear or position
+// the line it is written to. The caller must hold pb.mu.
+func (pb *ProgressBar) render() (string, error) {
+    defer pb.timeRender(time.Now())
 
-    if percent >= 100 {
-        pb.finished = true
-        fmt.Fprintf(pb.writer, "%s\n", output)
+    if c, ok := pb.clock.(*FrameClock); ok {
+        c.nextFrame()
+    }
+
+    pb.record()
+    state := pb.state()
+    pb.frame++
+    pb.blipDrawn = state.Active
+    state.Decorations = pb.decorate(state, true)
+
+    var frame []byte
+    if pb.lineMode == Forward {
+        return pb.forwardLine(), nil
+    } else if pb.lineMode == Plain && (pb.manager == nil || pb.ownLines) {
+        frame = []byte(pb.plainLine(state))
     } else {
-        fmt.Fprintf(pb.writer, "%s", output)
+        var err error
+        frame, err = pb.renderer.Frame(state)
+        if err != nil {
+            return "", err
+        }
     }
+
+    output := pb.affix(string(frame))
+    if pb.frameFilter != nil {
+        return pb.frameFilter(output), nil
+    }
+
+    return output, nil
 }
 
-// New will create a new progress bar using the default style.
+// sameWriter will determine whether two writers are the same. Writers
+// whose dynamic types cannot be compared are never considered equal.
+func sameWriter(a, b io.Writer) bool {
+    if a == nil || b == nil {
+        return a == b
+    }
+
+    if !reflect.TypeOf(a).Comparable() || !reflect.TypeOf(b).Comparable() {
+        return false
+    }
+
+    return a == b
+}
+
+// New will create a new progress bar using the default style, adapted
+// to the terminal background reported by DetectBackground().
 func New() *ProgressBar {
-    return NewWithStyle(DefaultStyle())
+    return newProgressBar(DefaultStyle().ForBackground(DetectBackground()))
 }
 
 // NewWithStyle will create a new progress bar using the specified
-// style object.
+// style object. If the progress bar is added to a Group, it keeps this
+// style instead of inheriting that of the group, as if it was set
+// using SetStyle().
 func NewWithStyle(style Style) *ProgressBar {
-    return &ProgressBar{
+    pb := newProgressBar(style)
+    pb.ownStyle = true
+    return pb
+}
+
+// newProgressBar will create a new progress bar using the specified
+// style object, which is replaced by the style of a Group it is added
+// to.
+func newProgressBar(style Style) *ProgressBar {
+    pb := &ProgressBar{
         style: style,
+        history: newSampleRing(DefaultHistoryCapacity),
         max: 100.0,
         showLabel: false,
         showPercentage: true,
+        renderer: NewLineRenderer(),
+        adaptive: true,
+        colorLevel: DetectColorLevel(),
+    }
+    pb.restyle()
+
+    if forwarding() {
+        pb.lineMode = Forward
     }
+
+    return pb
 }
 
 // DefaultStyle will retrieve the default Style for progress bars.
@@ -327,6 +911,8 @@ func DefaultStyle() Style {
         DoneChar: "\033[1;32m█\033[0m",
         NotDoneChar: "\033[1;37m░\033[0m",
         InProgressChar: "\033[1;37m░\033[0m",
+        FailedChar: "\033[1;31m█\033[0m",
+        SkippedChar: "\033[1;33m█\033[0m",
     }
 }
 
@@ -350,6 +936,10 @@ func LineStyle() Style {
         DoneChar: "\033[1;32m═\033[0m",
         NotDoneChar: "\033[1;37m─\033[0m",
         InProgressChar: "\033[1;37m─\033[0m",
+        FailedChar: "\033[1;31m═\033[0m",
+        SkippedChar: "\033[1;33m═\033[0m",
+        FinishedOpenChar: "\033[1;32m╠\033[0m",
+        FinishedCloseChar: "\033[1;32m╣\033[0m",
     }
 }
 
@@ -365,8 +955,11 @@ func LineStyleNoColor() Style {
     }
 }
 
-const ansi  = "[\u001B\u009B][[\\]()#;?]*(?:(?:(?:[a-zA-Z\\d]*(?:;[a-zA-Z\\d]*)*)?\u0007)|(?:(?:\\d{1,4}(?:;\\d{0,4})*)?[\\dA-PRZcf-ntqry=><~]))"
+// ansi matches OSC sequences such as OSC 8 hyperlinks, terminated by
+// BEL or ST, and CSI and other escape sequences.
+const ansi  = "\u001B\\][^\u0007\u001B]*(?:\u0007|\u001B\\\\)|" +
+              "[\u001B\u009B][[\\]()#;?]*(?:(?:(?:[a-zA-Z\\d]*(?:;[a-zA-Z\\d]*)*)?\u0007)|(?:(?:\\d{1,4}(?:;\\d{0,4})*)?[\\dA-PRZcf-ntqry=><~]))"
 var ansi_re = regexp.MustCompile(ansi)
 func strLen(s string) int {
-    return utf8.RuneCountInString(ansi_re.ReplaceAllString(s, ""))
+    return VisibleWidth(s)
 }
\ No newline at end of file
@@ -0,0 +1,91 @@
+// Package progresstest provides helpers for testing code that uses
+// progresscli.
+package progresstest
+
+import (
+    "sync"
+    "time"
+
+    "github.com/nathan-fiscaletti/progresscli-go"
+)
+
+// FakeClock is a progresscli.Clock whose time only changes when it is
+// explicitly advanced. Tickers created by a FakeClock fire when the
+// clock is advanced past their next tick. You should initialize a new
+// fake clock using the NewFakeClock() function.
+type FakeClock struct {
+    mu      sync.Mutex
+    now     time.Time
+    tickers []*fakeTicker
+}
+
+// NewFakeClock will create a new FakeClock set to the specified time.
+func NewFakeClock(now time.Time) *FakeClock {
+    return &FakeClock{now: now}
+}
+
+// Now will retrieve the current time of the fake clock.
+func (c *FakeClock) Now() time.Time {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+
+    return c.now
+}
+
+// NewTicker will create a new ticker that fires every d of fake time.
+func (c *FakeClock) NewTicker(d time.Duration) progresscli.Ticker {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+
+    t := &fakeTicker{
+        clock: c,
+        c: make(chan time.Time, 1),
+        interval: d,
+        next: c.now.Add(d),
+    }
+    c.tickers = append(c.tickers, t)
+    return t
+}
+
+// Advance will move the fake clock forward by d, firing any tickers
+// whose next tick falls within that time. Like a time.Ticker, a
+// ticker whose previous tick has not been received yet drops ticks.
+func (c *FakeClock) Advance(d time.Duration) {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+
+    c.now = c.now.Add(d)
+    for _, t := range c.tickers {
+        for !t.next.After(c.now) {
+            select {
+            case t.c <- t.next:
+            default:
+            }
+            t.next = t.next.Add(t.interval)
+        }
+    }
+}
+
+type fakeTicker struct {
+    clock    *FakeClock
+    c        chan time.Time
+    interval time.Duration
+    next     time.Time
+}
+
+func (t *fakeTicker) C() <-chan time.Time {
+    return t.c
+}
+
+func (t *fakeTicker) Stop() {
+    t.clock.mu.Lock()
+    defer t.clock.mu.Unlock()
+
+    for i, other := range t.clock.tickers {
+        if other == t {
+            t.clock.tickers = append(
+                t.clock.tickers[:i], t.clock.tickers[i+1:]...)
+            break
+        }
+    }
+}
@@ -0,0 +1,52 @@
+package progresstest
+
+import (
+    "sync"
+)
+
+// FakeSize is a progresscli.SizeProvider and progresscli.ResizeNotifier
+// whose size only changes when it is explicitly resized, so that tests
+// can render progress bars at a known width and exercise resizes. You
+// should initialize a new fake size using the NewFakeSize() function.
+type FakeSize struct {
+    mu      sync.Mutex
+    cols    int
+    rows    int
+    resized chan struct{}
+}
+
+// NewFakeSize will create a new FakeSize with the specified number of
+// columns and rows.
+func NewFakeSize(cols, rows int) *FakeSize {
+    return &FakeSize{
+        cols: cols,
+        rows: rows,
+        resized: make(chan struct{}, 1),
+    }
+}
+
+// Size will retrieve the number of columns and rows of the fake size.
+func (s *FakeSize) Size() (int, int) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    return s.cols, s.rows
+}
+
+// Resized will retrieve the channel notified by Resize().
+func (s *FakeSize) Resized() <-chan struct{} {
+    return s.resized
+}
+
+// Resize will change the number of columns and rows of the fake size
+// and notify the progress bars using it.
+func (s *FakeSize) Resize(cols, rows int) {
+    s.mu.Lock()
+    s.cols, s.rows = cols, rows
+    s.mu.Unlock()
+
+    select {
+    case s.resized <- struct{}{}:
+    default:
+    }
+}
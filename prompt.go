@@ -0,0 +1,159 @@
+package progresscli
+
+import (
+    "fmt"
+    "os"
+    "path/filepath"
+    "sync"
+)
+
+// PromptFile will retrieve the default path of the file written by
+// SetPromptFile(). It is named after the ID of the parent process, so
+// that a shell can find the file of the command it is running using
+// its own ID, e.g. "${TMPDIR:-/tmp}/progresscli-$$.prompt". Since it
+// only depends on the parent process, every progress bar of the
+// process using it shares the same file.
+func PromptFile() string {
+    name := fmt.Sprintf("progresscli-%d.prompt", os.Getppid())
+    return filepath.Join(os.TempDir(), name)
+}
+
+// SetPromptFile will keep the specified file updated with the label
+// and whole percentage of the progress bar, e.g. "build 42%", so that
+// shell prompts and status bars such as starship or the tmux status
+// line can display the progress of a long running job. The file is
+// rewritten each time the whole percentage changes and removed once
+// the progress bar finishes or is aborted. The file is written in the
+// background, so that writing it never holds up the progress bar. An
+// empty path uses the path retrieved by PromptFile(), which is shared
+// by every progress bar of the process, so only one progress bar at a
+// time should use it. Errors writing the file are ignored, since they
+// must not interrupt the job being tracked.
+func (pb *ProgressBar) SetPromptFile(path string) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    if path == "" {
+        path = PromptFile()
+    }
+
+    pb.removePrompt()
+    pb.promptPath = path
+    pb.updatePrompt(false)
+}
+
+// updatePrompt will write the prompt file of the progress bar if its
+// text has changed, or remove it if final is true. The caller must
+// hold pb.mu.
+func (pb *ProgressBar) updatePrompt(final bool) {
+    if pb.promptPath == "" || !pb.visible {
+        return
+    }
+
+    if final {
+        pb.removePrompt()
+        return
+    }
+
+    text := pb.progressText()
+    if text == pb.promptText {
+        return
+    }
+
+    pb.promptText = text
+    pb.promptWriter.queue(pb.promptPath, text)
+}
+
+// removePrompt will remove the prompt file of the progress bar, if it
+// has been written. The caller must hold pb.mu.
+func (pb *ProgressBar) removePrompt() {
+    if pb.promptText == "" {
+        return
+    }
+
+    pb.promptWriter.remove(pb.promptPath)
+    pb.promptText = ""
+}
+
+// promptWriter writes the prompt file of a progress bar in the
+// background, so that the progress bar isn't locked while it is
+// written. Only the latest text waiting to be written is kept, and
+// text is never written after the file has been removed.
+type promptWriter struct {
+    // mu protects the fields below. It is never held while writing.
+    mu      sync.Mutex
+    path    string
+    text    string
+    pending bool
+    running bool
+    seq     uint64
+
+    // writing is held while the file is written or removed, and done
+    // is the sequence number of the newest text written, or of the
+    // removal of the file.
+    writing sync.Mutex
+    done    uint64
+}
+
+// queue will write text to the file at path in the background,
+// replacing any text still waiting to be written.
+func (w *promptWriter) queue(path, text string) {
+    w.mu.Lock()
+    defer w.mu.Unlock()
+
+    w.seq++
+    w.path = path
+    w.text = text
+    w.pending = true
+    if !w.running {
+        w.running = true
+        go w.run()
+    }
+}
+
+// remove will remove the file at path right away, once any write in
+// progress has completed, and discard the text waiting to be written.
+func (w *promptWriter) remove(path string) {
+    w.mu.Lock()
+    w.seq++
+    seq := w.seq
+    w.pending = false
+    w.mu.Unlock()
+
+    w.writing.Lock()
+    defer w.writing.Unlock()
+
+    w.done = seq
+    os.Remove(path)
+}
+
+// run will write the queued text until there is none left.
+func (w *promptWriter) run() {
+    for {
+        w.mu.Lock()
+        if !w.pending {
+            w.running = false
+            w.mu.Unlock()
+            return
+        }
+        path, text, seq := w.path, w.text, w.seq
+        w.pending = false
+        w.mu.Unlock()
+
+        w.write(path, text, seq)
+    }
+}
+
+// write will write text to the file at path unless newer text has been
+// written or the file has been removed since it was queued.
+func (w *promptWriter) write(path, text string, seq uint64) {
+    w.writing.Lock()
+    defer w.writing.Unlock()
+
+    if seq <= w.done {
+        return
+    }
+
+    w.done = seq
+    replaceFile(path, []byte(text+"\n"))
+}
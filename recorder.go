@@ -0,0 +1,404 @@
+package progresscli
+
+import (
+    "encoding/json"
+    "fmt"
+    "io"
+    "os"
+    "strings"
+    "sync"
+    "sync/atomic"
+    "text/tabwriter"
+    "time"
+)
+
+// DefaultStallAfter is the time a progress bar must go without making
+// progress before a Recorder considers it stalled, unless the
+// recorder's StallAfter is set.
+const DefaultStallAfter = 5 * time.Second
+
+// DefaultMaxTasks is the number of timelines a Recorder keeps, unless
+// the recorder's MaxTasks is set.
+const DefaultMaxTasks = 1000
+
+// DefaultMaxStalls is the number of stalls a Recorder keeps for each
+// timeline, unless the recorder's MaxStalls is set.
+const DefaultMaxStalls = 32
+
+// timelineWidth is the width of the TIMELINE column written by
+// Recorder.WriteText().
+const timelineWidth = 30
+
+// Recorder captures the lifecycle of every progress bar shown while
+// it is set using SetRecorder(): when each one started, when it made
+// no progress for a while and when it finished or was aborted. Once
+// the run is done, the recorded timeline can be written as a report
+// using WriteText() or WriteJSON(), which makes it a simple profiler
+// for CLI workflows. You should initialize a new recorder using the
+// NewRecorder() function.
+type Recorder struct {
+    // StallAfter is the time a progress bar must go without making
+    // progress to be considered stalled. If zero, DefaultStallAfter is
+    // used.
+    StallAfter time.Duration
+
+    // MaxTasks is the number of timelines kept. Once it is reached,
+    // the oldest timeline of a progress bar that has ended is dropped
+    // for each new one; timelines of running progress bars are always
+    // kept. If zero, DefaultMaxTasks is used.
+    MaxTasks int
+
+    // MaxStalls is the number of stalls kept for each timeline, of
+    // which the oldest are dropped first. Dropped stalls still count
+    // towards TaskTimeline.Stalled(). If zero, DefaultMaxStalls is
+    // used.
+    MaxStalls int
+
+    // mu protects tasks. It is only taken when a progress bar is
+    // shown and when the timelines are retrieved, since each task has
+    // a lock of its own for updates.
+    mu    sync.Mutex
+    tasks []*recordedTask
+}
+
+// recordedTask is a timeline being captured by a Recorder. Each has a
+// lock of its own, so that progress bars updating their timelines
+// don't contend for the lock of the recorder.
+type recordedTask struct {
+    recorder *Recorder
+    mu       sync.Mutex
+    timeline TaskTimeline
+}
+
+// TaskTimeline is the lifecycle of a progress bar captured by a
+// Recorder.
+type TaskTimeline struct {
+    // Label is the label of the progress bar.
+    Label   string    `json:"label"`
+
+    // Started is the time the progress bar was shown and Ended the
+    // time it finished or was aborted, which is zero while it is
+    // still running.
+    Started time.Time `json:"started"`
+    Ended   time.Time `json:"ended"`
+
+    // Phase is the name of the phase the progress bar was last seen
+    // in, see Phase.
+    Phase   string    `json:"phase"`
+
+    // Stalls are the most recent periods in which the progress bar
+    // made no progress for at least the recorder's StallAfter, up to
+    // the recorder's MaxStalls.
+    Stalls  []Stall   `json:"stalls,omitempty"`
+
+    value    float64
+    progress time.Time
+    clock    Clock
+    stalled  time.Duration
+}
+
+// Stall is a period in which a progress bar made no progress.
+type Stall struct {
+    Start    time.Time     `json:"start"`
+    Duration time.Duration `json:"duration"`
+}
+
+// recording holds the *Recorder set using SetRecorder().
+var recording atomic.Value
+
+// NewRecorder will create a new Recorder without any timelines.
+func NewRecorder() *Recorder {
+    return &Recorder{}
+}
+
+// SetRecorder will start capturing the lifecycle of all progress bars
+// of the process using the specified recorder, replacing the recorder
+// set previously. Passing nil stops recording.
+func SetRecorder(r *Recorder) {
+    recording.Store(r)
+}
+
+// activeRecorder will retrieve the recorder set using SetRecorder(),
+// or nil if none is set.
+func activeRecorder() *Recorder {
+    r, _ := recording.Load().(*Recorder)
+    return r
+}
+
+// Duration will retrieve the time the progress bar ran for, up to now
+// according to the clock of the progress bar if it is still running.
+func (t TaskTimeline) Duration() time.Duration {
+    if t.Ended.IsZero() {
+        if t.clock != nil {
+            return t.clock.Now().Sub(t.Started)
+        }
+
+        return time.Since(t.Started)
+    }
+
+    return t.Ended.Sub(t.Started)
+}
+
+// Stalled will retrieve the total time the progress bar was stalled,
+// including the stalls that are no longer kept in Stalls.
+func (t TaskTimeline) Stalled() time.Duration {
+    var total time.Duration
+    for _, s := range t.Stalls {
+        total += s.Duration
+    }
+
+    if t.stalled > total {
+        return t.stalled
+    }
+
+    return total
+}
+
+// Tasks will retrieve the timelines recorded so far, in the order the
+// progress bars were shown.
+func (r *Recorder) Tasks() []TaskTimeline {
+    r.mu.Lock()
+    recorded := append([]*recordedTask(nil), r.tasks...)
+    r.mu.Unlock()
+
+    tasks := make([]TaskTimeline, len(recorded))
+    for i, t := range recorded {
+        t.mu.Lock()
+        tasks[i] = t.timeline
+        tasks[i].Stalls = append([]Stall(nil), t.timeline.Stalls...)
+        t.mu.Unlock()
+    }
+
+    return tasks
+}
+
+// WriteJSON will write the timelines recorded so far to w as a JSON
+// array.
+func (r *Recorder) WriteJSON(w io.Writer) error {
+    enc := json.NewEncoder(w)
+    enc.SetIndent("", "  ")
+    return enc.Encode(r.Tasks())
+}
+
+// WriteText will write an aligned report of the timelines recorded so
+// far to w, with one row per progress bar showing when it started
+// relative to the first one, how long it ran and was stalled for, its
+// phase and a timeline in which # marks progress and . marks stalls,
+// for example:
+//
+//     TASK      START  DURATION  STALLED  STATUS    TIMELINE
+//     download  +0s    12.4s     4.1s     Finished  ###.....####
+//     extract   +12s   3.02s     0s       Finished              ###
+func (r *Recorder) WriteText(w io.Writer) error {
+    tasks := r.Tasks()
+
+    var origin, end time.Time
+    for _, t := range tasks {
+        ended := t.Ended
+        if ended.IsZero() {
+            ended = t.Started.Add(t.Duration())
+        }
+
+        if origin.IsZero() || t.Started.Before(origin) {
+            origin = t.Started
+        }
+        if ended.After(end) {
+            end = ended
+        }
+    }
+
+    tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+    fmt.Fprintln(tw, "TASK\tSTART\tDURATION\tSTALLED\tSTATUS\tTIMELINE")
+
+    for _, t := range tasks {
+        fmt.Fprintf(tw, "%s\t+%s\t%s\t%s\t%s\t%s\n",
+            ansi_re.ReplaceAllString(t.Label, ""),
+            t.Started.Sub(origin).Round(time.Second),
+            t.Duration().Round(time.Millisecond),
+            t.Stalled().Round(time.Millisecond), t.Phase,
+            timeline(t, origin, end.Sub(origin)))
+    }
+
+    return tw.Flush()
+}
+
+// PrintText will print the report written by WriteText() to STDOUT.
+func (r *Recorder) PrintText() {
+    r.WriteText(os.Stdout)
+}
+
+// timeline will draw the timeline of a task within a run starting at
+// origin and lasting span.
+func timeline(t TaskTimeline, origin time.Time, span time.Duration) string {
+    if span <= 0 {
+        return strings.Repeat("#", timelineWidth)
+    }
+
+    column := func(at time.Time) int {
+        c := int(float64(at.Sub(origin)) / float64(span) * timelineWidth)
+        if c >= timelineWidth {
+            c = timelineWidth - 1
+        }
+        return c
+    }
+
+    lane := []byte(strings.Repeat(" ", timelineWidth))
+    last := column(t.Started.Add(t.Duration()))
+    for c := column(t.Started); c <= last; c++ {
+        lane[c] = '#'
+    }
+    for _, s := range t.Stalls {
+        for c := column(s.Start); c <= column(s.Start.Add(s.Duration)); c++ {
+            lane[c] = '.'
+        }
+    }
+
+    return strings.TrimRight(string(lane), " ")
+}
+
+// stallAfter will determine the time after which a progress bar is
+// considered stalled.
+func (r *Recorder) stallAfter() time.Duration {
+    if r.StallAfter > 0 {
+        return r.StallAfter
+    }
+
+    return DefaultStallAfter
+}
+
+// maxTasks will determine the number of timelines kept.
+func (r *Recorder) maxTasks() int {
+    if r.MaxTasks > 0 {
+        return r.MaxTasks
+    }
+
+    return DefaultMaxTasks
+}
+
+// maxStalls will determine the number of stalls kept per timeline.
+func (r *Recorder) maxStalls() int {
+    if r.MaxStalls > 0 {
+        return r.MaxStalls
+    }
+
+    return DefaultMaxStalls
+}
+
+// add will add a new task to the recorder, dropping the oldest task
+// that has ended if the recorder is full.
+func (r *Recorder) add(t *recordedTask) {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    if len(r.tasks) >= r.maxTasks() {
+        for i, old := range r.tasks {
+            old.mu.Lock()
+            ended := !old.timeline.Ended.IsZero()
+            old.mu.Unlock()
+
+            if ended {
+                r.tasks = append(r.tasks[:i], r.tasks[i+1:]...)
+                break
+            }
+        }
+    }
+
+    r.tasks = append(r.tasks, t)
+}
+
+// stall will record the period since the last progress of the task as
+// a stall if it lasted long enough, dropping the oldest stall if the
+// task has as many as the recorder keeps. The caller must hold t.mu.
+func (t *recordedTask) stall(now time.Time) {
+    tl := &t.timeline
+    gap := now.Sub(tl.progress)
+    if gap < t.recorder.stallAfter() {
+        return
+    }
+
+    tl.stalled += gap
+    tl.Stalls = append(tl.Stalls, Stall{tl.progress, gap})
+    if excess := len(tl.Stalls) - t.recorder.maxStalls(); excess > 0 {
+        tl.Stalls = append(tl.Stalls[:0], tl.Stalls[excess:]...)
+    }
+}
+
+// recordedTask will retrieve the task of the progress bar being
+// captured by the active recorder, or nil if there is none. The
+// caller must hold pb.mu.
+func (pb *ProgressBar) recordedTask() *recordedTask {
+    t := pb.recorded
+    if t == nil || t.recorder != activeRecorder() {
+        return nil
+    }
+
+    return t
+}
+
+// recordStart will record that the progress bar was shown, if a
+// recorder is set. The caller must hold pb.mu.
+func (pb *ProgressBar) recordStart() {
+    r := activeRecorder()
+    if r == nil {
+        return
+    }
+
+    t := pb.recordedTask()
+    if t == nil {
+        t = &recordedTask{recorder: r}
+        pb.recorded = t
+        r.add(t)
+    }
+
+    t.mu.Lock()
+    defer t.mu.Unlock()
+
+    now := pb.now()
+    t.timeline = TaskTimeline{
+        Label: pb.label,
+        Started: now,
+        Phase: Running.String(),
+        value: pb.value,
+        progress: now,
+        clock: pb.clock,
+    }
+}
+
+// recordProgress will record the current value of the progress bar,
+// if a recorder is set. The caller must hold pb.mu.
+func (pb *ProgressBar) recordProgress() {
+    t := pb.recordedTask()
+    if t == nil {
+        return
+    }
+
+    t.mu.Lock()
+    defer t.mu.Unlock()
+
+    t.timeline.Label = pb.label
+    if pb.value != t.timeline.value {
+        now := pb.now()
+        t.stall(now)
+        t.timeline.value = pb.value
+        t.timeline.progress = now
+    }
+}
+
+// recordEnd will record that the progress bar finished or was aborted,
+// if a recorder is set. The caller must hold pb.mu.
+func (pb *ProgressBar) recordEnd() {
+    t := pb.recordedTask()
+    if t == nil {
+        return
+    }
+    pb.recorded = nil
+
+    t.mu.Lock()
+    defer t.mu.Unlock()
+
+    now := pb.now()
+    t.stall(now)
+    t.timeline.Label = pb.label
+    t.timeline.Ended = now
+    t.timeline.Phase = pb.phase().String()
+}
@@ -0,0 +1,156 @@
+package progresscli
+
+import (
+    "time"
+)
+
+// SetRefreshInterval will set the interval at which the progress bar
+// is redrawn. By default, a progress bar is only redrawn when it
+// changes. With a refresh interval, the progress bar is redrawn on
+// every tick so that spinners and time based decorators keep moving,
+// and changes made between ticks are coalesced into a single frame
+// instead of being drawn immediately. A value of zero restores the
+// default behavior.
+func (pb *ProgressBar) SetRefreshInterval(d time.Duration) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    pb.stopTicker()
+    pb.refreshInterval = d
+    if pb.visible && !pb.finished {
+        pb.startTicker()
+    }
+}
+
+// startTicker will start the goroutine redrawing the progress bar on
+// every tick of its refresh interval, or of the shortest interval of
+// its timed decorators if it has no refresh interval, along with the
+// one redrawing it when the terminal is resized. Neither is started
+// unless the progress bar is running, so that finished, hidden and
+// paused progress bars don't use any CPU. The caller must hold pb.mu.
+func (pb *ProgressBar) startTicker() {
+    if !pb.running() {
+        return
+    }
+
+    pb.watchResize()
+    interval := pb.tickInterval()
+    if interval <= 0 || pb.tickerDone != nil {
+        return
+    }
+
+    ticker := pb.newTicker(interval)
+    done := make(chan struct{})
+    pb.tickerDone = done
+
+    go func() {
+        defer ticker.Stop()
+        for {
+            select {
+            case <-ticker.C():
+                pb.tick()
+            case <-done:
+                return
+            }
+        }
+    }()
+}
+
+// tickInterval will determine the interval at which the progress bar
+// needs to be redrawn even if it doesn't change, including the frames
+// of eased jumps. It is zero if the progress bar only needs to be
+// redrawn when it changes. The caller must hold pb.mu.
+func (pb *ProgressBar) tickInterval() time.Duration {
+    interval := pb.baseTickInterval()
+    if interval == 0 && pb.easing > 0 {
+        interval = pb.easing / easeFrames
+    }
+    if pb.status && (interval == 0 || activityBlip < interval) {
+        interval = activityBlip
+    }
+    if pb.gauge != nil && (interval == 0 || gaugeInterval < interval) {
+        interval = gaugeInterval
+    }
+    if pb.counter && (interval == 0 || counterInterval < interval) {
+        interval = counterInterval
+    }
+    if pb.averaging() && (interval == 0 || smoothInterval < interval) {
+        interval = smoothInterval
+    }
+
+    return interval
+}
+
+// baseTickInterval will determine the interval at which the progress
+// bar needs to be redrawn even if it doesn't change, apart from the
+// frames of eased jumps. The caller must hold pb.mu.
+func (pb *ProgressBar) baseTickInterval() time.Duration {
+    if pb.refreshInterval > 0 {
+        return pb.refreshInterval
+    }
+
+    var interval time.Duration
+    for _, d := range pb.decorators {
+        if d.interval > 0 && (interval == 0 || d.interval < interval) {
+            interval = d.interval
+        }
+    }
+
+    // Without a step, the Plain and JSON line modes write a line
+    // every interval even if the progress bar is stalled.
+    if (pb.lineMode == Plain || pb.lineMode == JSON) &&
+       pb.plain.Step <= 0 && pb.plain.Interval > 0 &&
+       (interval == 0 || pb.plain.Interval < interval) {
+        interval = pb.plain.Interval
+    }
+
+    return interval
+}
+
+// stopTicker will stop the goroutines started by startTicker(), if
+// they are running. The caller must hold pb.mu.
+func (pb *ProgressBar) stopTicker() {
+    pb.unwatchResize()
+    if pb.tickerDone != nil {
+        close(pb.tickerDone)
+        pb.tickerDone = nil
+    }
+}
+
+// running will determine whether the progress bar is visible and
+// neither finished nor paused. The caller must hold pb.mu.
+func (pb *ProgressBar) running() bool {
+    return pb.visible && !pb.finished && !pb.paused
+}
+
+// tick will redraw the progress bar in response to a tick of its
+// refresh interval.
+func (pb *ProgressBar) tick() {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+    defer pb.recoverRender()
+
+    if !pb.visible || pb.finished || pb.paused || pb.suspend.active() ||
+       pb.backoff() {
+        return
+    }
+
+    pb.sampleGauge()
+    moved := pb.settle()
+
+    // If the ticker only runs for easing, the activity indicator, a
+    // gauge or smoothing, there is nothing to draw between jumps other
+    // than the final frame of the last one, or while the indicator and
+    // the displayed value stay the same. Gauges are redrawn by
+    // sampleGauge() when they change.
+    if pb.baseTickInterval() == 0 && !pb.animating() &&
+       !pb.blipChanged() && !moved {
+        if pb.easeStart.IsZero() {
+            return
+        }
+
+        pb.easeStart = time.Time{}
+    }
+
+    pb.requestDraw()
+}
@@ -0,0 +1,628 @@
+package progresscli
+
+import (
+    "fmt"
+    "math"
+    "strings"
+    "sync"
+)
+
+// Renderer turns a snapshot of a progress bar into the bytes of a
+// single frame. The progress bar takes care of positioning the frame,
+// so a renderer should not emit carriage returns or line clearing
+// sequences. Implementing a Renderer allows for alternative output
+// formats without changing how the state of a progress bar is
+// tracked.
+type Renderer interface {
+    Frame(s State) ([]byte, error)
+}
+
+// LineRenderer is the default Renderer. It renders the progress bar
+// as a single line of text using the characters and ANSI escape
+// sequences of the progress bar's style, filling the width available
+// to it.
+//
+// The parts of a frame that rarely change, such as the verb, label and
+// open character, and the runs of done and not-done characters making
+// up the fill, are built once and reused for later frames, so that
+// rendering a frame on a wide terminal mostly consists of copying
+// prebuilt strings. You should initialize a new line renderer using
+// the NewLineRenderer() function.
+type LineRenderer struct {
+    mu          sync.Mutex
+    prefixKey   prefixKey
+    prefix      string
+    doneChar    string
+    doneFill    string
+    failedChar  string
+    failedFill  string
+    skippedChar string
+    skippedFill string
+    notDoneChar string
+    notDoneFill string
+}
+
+// prefixKey identifies the inputs the cached prefix of a LineRenderer
+// was built from.
+type prefixKey struct {
+    verb      string
+    verbColor string
+    verbWidth int
+    label     string
+    open      string
+    padding   string
+}
+
+// NewLineRenderer will create a new LineRenderer.
+func NewLineRenderer() *LineRenderer {
+    return &LineRenderer{}
+}
+
+// SetRenderer will set the renderer used to produce the frames of the
+// progress bar. Passing nil restores the default LineRenderer.
+func (pb *ProgressBar) SetRenderer(r Renderer) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    if r == nil {
+        r = NewLineRenderer()
+    }
+
+    pb.renderer = r
+    pb.update()
+}
+
+// SetFrameFilter will set a function that is applied to each frame
+// produced by the renderer before it is written, for example to prefix
+// frames with a timestamp or to pass them through a colorizer. The
+// filter receives the frame without the sequences used to clear or
+// position the line and must not add any. Passing nil removes the
+// filter.
+func (pb *ProgressBar) SetFrameFilter(filter func(frame string) string) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    pb.frameFilter = filter
+    pb.update()
+}
+
+// Layout describes how the columns of a frame rendered by a
+// LineRenderer are divided between its parts. Layouts are computed by
+// Measure() and Arrange(), before the frame is painted.
+type Layout struct {
+    // Verb, Label, Percent and Decorations are the widths of the verb
+    // column, the label, the percentage and the decorations, including
+    // the spaces separating them from the rest of the frame.
+    Verb        int
+    Label       int
+    Percent     int
+    Decorations int
+
+    // Open and Close are the widths of the open and close characters,
+    // including the padding of the style.
+    Open        int
+    Close       int
+
+    // Fill is the width of the section between the open and close
+    // characters. On a status line, see NewStatus(), it is the width
+    // of the activity indicator and the time of the last activity, and
+    // on a counter, see NewByteCounter(), the width of its text.
+    Fill        int
+
+    // Compact is true if the bar does not fit in the available width
+    // and only its verb, label and percentage are displayed as text.
+    // In a compact layout, Label is the width of the text displayed in
+    // place of the bar and all widths other than Verb and Percent are
+    // included in it.
+    Compact     bool
+}
+
+// Width will compute the number of columns taken up by a frame with
+// the layout.
+func (l Layout) Width() int {
+    return l.Verb + l.Label + l.Open + l.Fill + l.Close + l.Percent +
+           l.Decorations
+}
+
+// Measurer is implemented by renderers that can compute the layout of
+// a frame before rendering it, so that callers arranging other output
+// around a progress bar can find out how much space it needs.
+type Measurer interface {
+    // Measure will compute the layout of the smallest frame in which
+    // the bar is displayed in full.
+    Measure(s State) Layout
+
+    // Arrange will compute the layout of a frame filling the specified
+    // width.
+    Arrange(s State, width int) Layout
+}
+
+// Measure will compute the layout of the progress bar's frame using
+// its renderer, including the text of its decorators. If the renderer
+// does not implement Measurer, false is returned.
+func (pb *ProgressBar) Measure() (Layout, bool) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    m, ok := pb.renderer.(Measurer)
+    if !ok {
+        return Layout{}, false
+    }
+
+    state := pb.state()
+    state.Decorations = pb.decorate(state, false)
+    return m.Measure(state), true
+}
+
+// finishedCaps will replace the open and close characters of the
+// state's style with their finished variants if the progress bar is
+// complete.
+func finishedCaps(s State) State {
+    if !s.Complete {
+        return s
+    }
+
+    if s.StyleWidths == (StyleWidths{}) {
+        s.StyleWidths = measureStyle(s.Style)
+    }
+
+    if s.Style.FinishedOpenChar != "" {
+        s.Style.OpenChar = s.Style.FinishedOpenChar
+        s.StyleWidths.Open = s.StyleWidths.FinishedOpen
+    }
+
+    if s.Style.FinishedCloseChar != "" {
+        s.Style.CloseChar = s.Style.FinishedCloseChar
+        s.StyleWidths.Close = s.StyleWidths.FinishedClose
+    }
+
+    return s
+}
+
+// lineParts holds the text of the parts of a frame surrounding the
+// fill.
+type lineParts struct {
+    widths       StyleWidths
+    verbColumn   string
+    percentLabel string
+    decorations  string
+    status       string
+}
+
+// parts will build the text of the parts of a frame surrounding the
+// fill.
+func (r *LineRenderer) parts(s State) lineParts {
+    p := lineParts{widths: s.StyleWidths}
+    if p.widths == (StyleWidths{}) {
+        p.widths = measureStyle(s.Style)
+    }
+
+    if s.ShowPercentage {
+        if s.Indeterminate {
+            p.percentLabel = "--%"
+        } else if s.ShowPercentageDecimal {
+            p.percentLabel = fmt.Sprintf("%.2f%%", s.Percent)
+        } else {
+            p.percentLabel = fmt.Sprintf("%.0f%%", s.Percent)
+        }
+    }
+
+    if strLen(s.Verb) > 0 {
+        p.verbColumn = renderVerbColumn(s)
+    }
+
+    for _, text := range s.Decorations {
+        p.decorations += " " + text
+    }
+
+    if s.Status {
+        p.status = statusText(s)
+    } else if s.Counter {
+        p.status = counterText(s)
+    }
+
+    return p
+}
+
+// fitDecorations will join the decorations, each preceded by a space,
+// into at most width columns. Decorations that don't fit are dropped,
+// starting with the last one, and if not even the first one fits, it
+// is truncated.
+func fitDecorations(decorations []string, width int) string {
+    var output string
+    for _, text := range decorations {
+        if strLen(output)+1+strLen(text) > width {
+            break
+        }
+
+        output += " " + text
+    }
+
+    if output == "" && len(decorations) > 0 && width > 1 {
+        output = " " + TruncateToWidth(decorations[0], width-1, "…")
+    }
+
+    return output
+}
+
+// Measure will compute the layout of the smallest frame in which the
+// bar is displayed in full, with a fill just wide enough for one of
+// each of the done, not-done and in-progress characters.
+func (r *LineRenderer) Measure(s State) Layout {
+    s = finishedCaps(s)
+    return r.measure(s, r.parts(s))
+}
+
+func (r *LineRenderer) measure(s State, p lineParts) Layout {
+    l := Layout{
+        Verb: strLen(p.verbColumn),
+        Decorations: strLen(p.decorations),
+        Open: p.widths.Open + p.widths.Padding,
+        Close: p.widths.Close + p.widths.Padding,
+        Fill: p.widths.Done + p.widths.NotDone + p.widths.InProgress,
+    }
+
+    if s.Label != "" {
+        l.Label = strLen(s.Label) + 1
+    }
+
+    if s.Status || s.Counter {
+        l.Open, l.Close, l.Fill = 0, 0, strLen(p.status)
+    }
+
+    if s.ShowPercentage {
+        l.Percent = len(" 100%")
+        if s.ShowPercentageDecimal && !s.Indeterminate {
+            l.Percent = len(" 100.00%")
+        }
+    }
+
+    return l
+}
+
+// Arrange will compute the layout of a frame filling the specified
+// width. If the bar does not fit, the layout is compact.
+func (r *LineRenderer) Arrange(s State, width int) Layout {
+    s = finishedCaps(s)
+    return r.arrange(s, r.parts(s), width)
+}
+
+func (r *LineRenderer) arrange(s State, p lineParts, width int) Layout {
+    l := r.measure(s, p)
+    if s.Status || s.Counter {
+        // Status lines and counters that don't fit are cut off at
+        // the end, see paint().
+        if over := l.Width() - width; over > 0 && width > 0 {
+            cut := over
+            if cut > l.Fill {
+                cut = l.Fill
+            }
+            l.Fill -= cut
+            l.Decorations -= over - cut
+            if l.Decorations < 0 {
+                l.Decorations = 0
+            }
+        }
+
+        return l
+    }
+
+    if width >= l.Width() {
+        l.Fill += width - l.Width()
+        return l
+    }
+
+    // Make room for the minimum width of the bar by dropping or
+    // shortening decorations, see paint().
+    if s.MinBarWidth > 0 {
+        fixed := l.Width() - l.Fill - l.Decorations
+        if room := width - fixed - s.MinBarWidth; room >= 0 {
+            l.Decorations = strLen(fitDecorations(s.Decorations, room))
+            l.Fill = width - fixed - l.Decorations
+            return l
+        }
+    }
+
+    compact := Layout{Verb: l.Verb, Compact: true}
+    switch {
+    case s.Label != "" && s.ShowPercentage:
+        compact.Label = strLen(s.Label) + 1
+        compact.Percent = strLen(p.percentLabel)
+    case s.ShowPercentage:
+        compact.Percent = strLen(p.percentLabel)
+    default:
+        compact.Label = len("Loading...")
+    }
+
+    // Labels too long for even the compact layout are truncated, see
+    // paint(), keeping the percentage.
+    if over := compact.Width() - width; over > 0 && width > 0 {
+        compact.Label -= over
+        if compact.Label < 0 {
+            compact.Label = 0
+        }
+    }
+
+    return compact
+}
+
+// Paint will render a frame using the specified layout, which should
+// have been computed for the same state using Measure() or Arrange().
+func (r *LineRenderer) Paint(s State, l Layout) ([]byte, error) {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    s = finishedCaps(s)
+    return r.paint(s, r.parts(s), l), nil
+}
+
+// paint will render a frame using the specified layout. The caller
+// must hold r.mu.
+func (r *LineRenderer) paint(s State, p lineParts, l Layout) []byte {
+    var output strings.Builder
+
+    if s.Status || s.Counter {
+        output.WriteString(p.verbColumn)
+        if s.Label != "" {
+            output.WriteString(s.Label + " ")
+        }
+        output.WriteString(p.status)
+        output.WriteString(p.decorations)
+        return []byte(TruncateToWidth(output.String(), l.Width(), "…"))
+    }
+
+    // Text is fitted to the widths of the layout in columns, so that
+    // a wide character cut off at the end of a truncated label is
+    // padded instead of shifting the percentage.
+    if l.Compact {
+        output.WriteString(p.verbColumn)
+        if s.Label != "" && s.ShowPercentage {
+            if l.Label > 1 {
+                output.WriteString(fitWidth(s.Label, l.Label-1) + " ")
+            }
+            output.WriteString(p.percentLabel)
+        } else if s.ShowPercentage {
+            output.WriteString(p.percentLabel)
+        } else {
+            output.WriteString(fitWidth("Loading...", l.Label))
+        }
+
+        return []byte(output.String())
+    }
+
+    inProgress := s.Style.InProgressChar
+    if len(s.Style.Spinner) > 0 {
+        inProgress = s.Style.Spinner[s.Frame%len(s.Style.Spinner)]
+    }
+
+    var closeChar string
+    if p.widths.Close > 0 {
+        closeChar = s.Style.CloseChar
+    }
+
+    fills := r.renderRows(s, inProgress, p.widths, l.Fill)
+
+    output.WriteString(r.cachedPrefix(s, p.verbColumn))
+    output.WriteString(fills[0])
+    output.WriteString(s.Style.Padding)
+    output.WriteString(closeChar)
+
+    if s.ShowPercentage {
+        output.WriteString(" " + s.Style.PercentageColor +
+            padLeft(p.percentLabel, 4))
+    }
+
+    if strLen(p.decorations) > l.Decorations {
+        output.WriteString(fitDecorations(s.Decorations, l.Decorations))
+    } else {
+        output.WriteString(p.decorations)
+    }
+
+    // The rows the bar wraps across are aligned with the first one.
+    indent := strings.Repeat(" ", l.Verb+l.Label)
+    for _, fill := range fills[1:] {
+        output.WriteString("\n" + indent + s.Style.OpenChar)
+        output.WriteString(s.Style.Padding + fill + s.Style.Padding)
+        output.WriteString(closeChar)
+    }
+
+    return []byte(output.String())
+}
+
+// Frame will render a frame of the progress bar filling the width of
+// the state. The frame is a single line unless the bar wraps across
+// several rows, see SetRows().
+func (r *LineRenderer) Frame(s State) ([]byte, error) {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    s = finishedCaps(s)
+    p := r.parts(s)
+    return r.paint(s, p, r.arrange(s, p, s.Width)), nil
+}
+
+// renderFill will render the fill of the progress bar for the
+// percentage of the state. The caller must hold r.mu.
+func (r *LineRenderer) renderFill(
+    s State, inProgress string, widths StyleWidths, available int,
+) string {
+    var output  strings.Builder
+    percent := s.Percent
+    fill := s.Fill()
+
+    var progressFillSize int
+    progressFillSize = available -
+                       widths.InProgress
+    filledBarLength := int(math.Trunc(fill *
+                           float64(progressFillSize)))
+    if filledBarLength < 0 {
+        filledBarLength = 0
+    }
+
+    // Skipped items are drawn at the start of the completed section
+    // and failed items at its end. When counting down, the fill
+    // represents the remaining work, so there is no completed section
+    // to draw them in.
+    var skippedBarLength, failedBarLength int
+    if s.Direction == Up {
+        skippedBarLength = segmentLength(
+            s.Skipped, s.Max, widths.Skipped, progressFillSize,
+            filledBarLength)
+        failedBarLength = segmentLength(
+            s.Failed, s.Max, widths.Failed, progressFillSize,
+            filledBarLength-skippedBarLength)
+    }
+
+    output.WriteString(repeatCached(
+        &r.skippedChar, &r.skippedFill, s.Style.SkippedChar,
+        skippedBarLength))
+    output.WriteString(repeatCached(
+        &r.doneChar, &r.doneFill, s.Style.DoneChar,
+        filledBarLength-skippedBarLength-failedBarLength))
+    output.WriteString(repeatCached(
+        &r.failedChar, &r.failedFill, s.Style.FailedChar,
+        failedBarLength))
+
+    if widths.InProgress > 0 {
+        if percent < 100 {
+            output.WriteString(inProgress)
+        } else {
+            output.WriteString(s.Style.DoneChar)
+        }
+    }
+
+    notDoneLength := available -
+                     filledBarLength -
+                     widths.InProgress
+    output.WriteString(r.markedTrack(
+        s, available-notDoneLength, available, progressFillSize))
+
+    return output.String()
+}
+
+// track will render n columns of the section of the bar that is not
+// done yet, either using the not-done character or, if the style has a
+// track color, as a band of spaces in that color. The caller must hold
+// r.mu.
+func (r *LineRenderer) track(s State, n int) string {
+    if s.Style.TrackColor == "" {
+        return repeatCached(
+            &r.notDoneChar, &r.notDoneFill, s.Style.NotDoneChar, n)
+    }
+
+    if n <= 0 {
+        return ""
+    }
+
+    return s.Style.TrackColor +
+           repeatCached(&r.notDoneChar, &r.notDoneFill, " ", n) +
+           "\033[0m"
+}
+
+// segmentLength will compute the number of columns of the fill used
+// to draw count items of a segment with its own character, rounding up
+// so a single item is visible. It is zero if the style has no
+// character for the segment and never exceeds limit.
+func segmentLength(count, max float64, width, size, limit int) int {
+    if width == 0 || count <= 0 || max <= 0 {
+        return 0
+    }
+
+    length := int(math.Ceil((count / max) * float64(size)))
+    if length > limit {
+        length = limit
+    }
+
+    return length
+}
+
+// renderBounce will render the fill of an indeterminate progress bar:
+// a done character that moves back and forth across the bar, one
+// column per frame. The caller must hold r.mu.
+func (r *LineRenderer) renderBounce(
+    s State, widths StyleWidths, available int,
+) string {
+    marker, markerWidth := s.Style.DoneChar, widths.Done
+
+    notDoneWidth := widths.NotDone
+    if notDoneWidth == 0 {
+        notDoneWidth = 1
+    }
+
+    cells := (available - markerWidth) / notDoneWidth
+    if cells < 0 {
+        cells = 0
+    }
+
+    position := 0
+    if cells > 0 {
+        position = s.Frame % (2 * cells)
+        if position > cells {
+            position = 2*cells - position
+        }
+    }
+
+    var output strings.Builder
+    output.WriteString(r.track(s, position))
+    output.WriteString(marker)
+    output.WriteString(r.track(s, cells-position))
+
+    return output.String()
+}
+
+// cachedPrefix will retrieve the part of the frame preceding the fill:
+// the verb column, the label, the open character and the padding. It
+// is only rebuilt when one of them changes. The caller must hold r.mu.
+func (r *LineRenderer) cachedPrefix(s State, verbColumn string) string {
+    key := prefixKey{
+        verb: s.Verb,
+        verbColor: s.Style.VerbColor,
+        verbWidth: s.Style.VerbWidth,
+        label: s.Label,
+        open: s.Style.OpenChar,
+        padding: s.Style.Padding,
+    }
+
+    if key != r.prefixKey || r.prefix == "" {
+        r.prefixKey = key
+        r.prefix = verbColumn
+        if s.Label != "" {
+            r.prefix += s.Label + " "
+        }
+        r.prefix += s.Style.OpenChar + s.Style.Padding
+    }
+
+    return r.prefix
+}
+
+// repeatCached will retrieve char repeated n times, slicing it from a
+// cached fill which is only rebuilt when the character changes or the
+// fill is too short.
+func repeatCached(cachedChar, cachedFill *string, char string, n int) string {
+    if n <= 0 || char == "" {
+        return ""
+    }
+
+    if *cachedChar != char || len(*cachedFill) < n*len(char) {
+        *cachedChar = char
+        *cachedFill = strings.Repeat(char, n)
+    }
+
+    return (*cachedFill)[:n*len(char)]
+}
+
+// renderVerbColumn will build the right-aligned verb column,
+// including the space separating it from the rest of the progress
+// bar.
+func renderVerbColumn(s State) string {
+    var padding string
+    if pad := s.Style.VerbWidth - strLen(s.Verb); pad > 0 {
+        padding = strings.Repeat(" ", pad)
+    }
+
+    if s.Style.VerbColor == "" {
+        return padding + s.Verb + " "
+    }
+
+    return padding + s.Style.VerbColor + s.Verb + "\033[0m "
+}
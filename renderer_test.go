@@ -0,0 +1,96 @@
+package progresscli
+
+import (
+    "strings"
+    "testing"
+)
+
+// renderCompact will render a frame of a progress bar with the
+// specified label and width at 42%, using a style without colors.
+func renderCompact(t *testing.T, label string, width int) string {
+    t.Helper()
+
+    pb := NewWithStyle(DefaultStyleNoColor())
+    pb.SetMaxWidth(width)
+    pb.SetLabel(label)
+    pb.SetValue(42)
+
+    frame, err := pb.render()
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    return frame
+}
+
+func TestCompactFitsWidth(t *testing.T) {
+    labels := []string{
+        "download",
+        "下载文件中",
+        "ファイルを転送",
+        "📦 パッケージ",
+        "mixed 混合 text",
+    }
+
+    for _, label := range labels {
+        for width := 6; width <= VisibleWidth(label)+6; width++ {
+            frame := renderCompact(t, label, width)
+            if got := VisibleWidth(frame); got > width {
+                t.Errorf("%q at width %d: %q is %d columns wide",
+                    label, width, frame, got)
+            }
+            if !strings.HasSuffix(frame, " 42%") {
+                t.Errorf("%q at width %d: %q lost the percentage",
+                    label, width, frame)
+            }
+        }
+    }
+}
+
+func TestCompactKeepsLabelThatFits(t *testing.T) {
+    label := "下载文件中"
+    frame := renderCompact(t, label, VisibleWidth(label)+4)
+    if frame != label+" 42%" {
+        t.Errorf("got %q, want %q", frame, label+" 42%")
+    }
+}
+
+func TestCompactPadsCutWideCharacter(t *testing.T) {
+    // At 10 columns, 5 are left for the label, which fits two wide
+    // characters, an ellipsis and a space in place of the third.
+    frame := renderCompact(t, "下载文件中", 10)
+    if got := VisibleWidth(frame); got != 10 {
+        t.Errorf("%q is %d columns wide, want 10", frame, got)
+    }
+    if !strings.HasPrefix(frame, "下载…") {
+        t.Errorf("%q doesn't start with the truncated label", frame)
+    }
+}
+
+func TestCompactLoadingFitsWidth(t *testing.T) {
+    for width := 1; width <= 12; width++ {
+        pb := NewWithStyle(DefaultStyleNoColor())
+        pb.SetMaxWidth(width)
+        pb.SetShowPercentage(false)
+
+        frame, err := pb.render()
+        if err != nil {
+            t.Fatal(err)
+        }
+        if got := VisibleWidth(frame); got > width {
+            t.Errorf("width %d: %q is %d columns wide", width, frame, got)
+        }
+    }
+}
+
+func TestPercentPaddedByColumns(t *testing.T) {
+    if got := padLeft("5%", 4); got != "  5%" {
+        t.Errorf("got %q, want %q", got, "  5%")
+    }
+    if got := padLeft("１%", 4); got != " １%" {
+        t.Errorf("got %q, want %q", got, " １%")
+    }
+    if got := padLeft("100%", 4); got != "100%" {
+        t.Errorf("got %q, want %q", got, "100%")
+    }
+}
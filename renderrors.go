@@ -0,0 +1,59 @@
+package progresscli
+
+import (
+    "errors"
+    "fmt"
+)
+
+// ErrInvalidSize is reported on the channel retrieved by Errors() when
+// a SizeProvider reports a terminal without any columns or rows. The
+// size of the terminal is detected as if no provider was set instead.
+var ErrInvalidSize = errors.New(
+    "progresscli: size provider reported an invalid terminal size")
+
+// errorsCapacity is the number of errors buffered by the channel
+// retrieved by Errors().
+const errorsCapacity = 16
+
+// Errors will retrieve a channel on which failures to display the
+// progress bar are reported instead of being ignored, so that an
+// application can log them or stop when its progress display breaks.
+// These include errors writing frames to the writer, errors returned
+// by the renderer, invalid sizes reported by a SizeProvider and panics
+// in renderers or decorators while the progress bar is redrawn in the
+// background, which are recovered. The channel buffers a few errors
+// and further errors are dropped until they have been received, so
+// that a progress bar never blocks on its errors. It is never closed.
+func (pb *ProgressBar) Errors() <-chan error {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    if pb.errors == nil {
+        pb.errors = make(chan error, errorsCapacity)
+    }
+
+    return pb.errors
+}
+
+// reportError will send an error on the channel retrieved by Errors(),
+// unless it hasn't been retrieved or is full. The caller must hold
+// pb.mu.
+func (pb *ProgressBar) reportError(err error) {
+    if err == nil || pb.errors == nil {
+        return
+    }
+
+    select {
+    case pb.errors <- err:
+    default:
+    }
+}
+
+// recoverRender will recover from a panic while the progress bar is
+// redrawn in the background and report it using reportError(). It
+// must be deferred while holding pb.mu.
+func (pb *ProgressBar) recoverRender() {
+    if r := recover(); r != nil {
+        pb.reportError(fmt.Errorf("progresscli: panic while rendering: %v", r))
+    }
+}
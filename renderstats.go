@@ -0,0 +1,53 @@
+package progresscli
+
+import (
+    "time"
+)
+
+// RenderStats holds statistics about the frames of a progress bar,
+// which can be used to verify that throttling works as intended and to
+// tune the refresh settings.
+type RenderStats struct {
+    // Frames is the number of frames rendered.
+    Frames       int
+
+    // Dropped is the number of updates that were not drawn right away
+    // because of the refresh interval, adaptive refresh or the
+    // granularity of the Plain line mode, and were coalesced into a
+    // later frame instead.
+    Dropped      int
+
+    // RenderTime is the total time spent rendering frames.
+    RenderTime   time.Duration
+
+    // BytesWritten is the number of bytes written to the writer of the
+    // progress bar. For a progress bar that belongs to a Manager, it
+    // is the size of the frames passed to the manager.
+    BytesWritten int64
+}
+
+// AverageRenderTime will compute the average time spent rendering a
+// frame.
+func (s RenderStats) AverageRenderTime() time.Duration {
+    if s.Frames == 0 {
+        return 0
+    }
+
+    return s.RenderTime / time.Duration(s.Frames)
+}
+
+// RenderStats will retrieve statistics about the frames rendered by
+// the progress bar since it was created.
+func (pb *ProgressBar) RenderStats() RenderStats {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    return pb.renderStats
+}
+
+// timeRender will record a frame whose rendering started at start. The
+// caller must hold pb.mu.
+func (pb *ProgressBar) timeRender(start time.Time) {
+    pb.renderStats.Frames++
+    pb.renderStats.RenderTime += time.Since(start)
+}
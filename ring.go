@@ -0,0 +1,112 @@
+package progresscli
+
+import (
+    "time"
+)
+
+// DefaultHistoryCapacity is the number of samples of a progress bar's
+// value kept for computing its rate. Each sample takes up 40 bytes,
+// so the history of a progress bar uses about 2.5 KiB by default.
+const DefaultHistoryCapacity = 64
+
+// sample is the value and cost of a progress bar at a point in time.
+type sample struct {
+    at    time.Time
+    value float64
+    cost  float64
+}
+
+// sampleRing is a fixed capacity ring buffer of samples. Once it is
+// full, pushing a sample overwrites the oldest one, so its memory use
+// stays constant however long a progress bar runs.
+type sampleRing struct {
+    buf   []sample
+    start int
+    n     int
+}
+
+func newSampleRing(capacity int) *sampleRing {
+    if capacity < 1 {
+        capacity = 1
+    }
+
+    return &sampleRing{buf: make([]sample, capacity)}
+}
+
+func (r *sampleRing) push(s sample) {
+    if r.n < len(r.buf) {
+        r.buf[(r.start+r.n)%len(r.buf)] = s
+        r.n++
+        return
+    }
+
+    r.buf[r.start] = s
+    r.start = (r.start + 1) % len(r.buf)
+}
+
+func (r *sampleRing) len() int {
+    return r.n
+}
+
+func (r *sampleRing) oldest() sample {
+    return r.buf[r.start]
+}
+
+func (r *sampleRing) newest() sample {
+    return r.buf[(r.start+r.n-1)%len(r.buf)]
+}
+
+func (r *sampleRing) reset() {
+    r.start = 0
+    r.n = 0
+}
+
+// SetHistoryCapacity will set the number of samples of the progress
+// bar's value that are kept for computing its rate. A larger history
+// smooths the rate and ETA over a longer period, at a cost of 40 bytes
+// per sample. The default is DefaultHistoryCapacity. Changing the
+// capacity discards the current history.
+func (pb *ProgressBar) SetHistoryCapacity(n int) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    pb.history = newSampleRing(n)
+}
+
+// start will record the current time as the start time of the
+// progress bar and clear its history. The caller must hold pb.mu.
+func (pb *ProgressBar) start() {
+    pb.started = pb.now()
+    pb.smoothed = pb.value
+    pb.history.reset()
+    pb.plainAt = time.Time{}
+    pb.recordStart()
+}
+
+// record will add the current value of the progress bar to its
+// history if it changed since the last sample. The caller must hold
+// pb.mu.
+func (pb *ProgressBar) record() {
+    if pb.history.len() > 0 && pb.history.newest().value == pb.value &&
+       pb.history.newest().cost == pb.cost {
+        return
+    }
+
+    pb.history.push(sample{at: pb.now(), value: pb.value, cost: pb.cost})
+}
+
+// rate will compute the rate of progress in units per second over the
+// samples in the progress bar's history. The caller must hold pb.mu.
+func (pb *ProgressBar) rate() float64 {
+    if pb.history.len() == 0 {
+        return 0
+    }
+
+    oldest := pb.history.oldest()
+    elapsed := pb.since(oldest.at).Seconds()
+    if elapsed <= 0 {
+        return 0
+    }
+
+    return (pb.value - oldest.value) / elapsed
+}
@@ -0,0 +1,97 @@
+package progresscli
+
+import (
+    "fmt"
+    "math"
+    "strings"
+)
+
+// SetRows will wrap the bar across the specified number of terminal
+// rows, drawing it as a filled rectangle that fills row by row. This
+// makes the bar easier to read from a distance, for example on kiosk
+// or dashboard displays, and gives long running progress bars a finer
+// resolution. The verb, label, percentage and decorations are
+// displayed on the first row. The default is a single row.
+func (pb *ProgressBar) SetRows(rows int) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    if rows < 1 {
+        rows = 1
+    }
+
+    pb.rows = rows
+    pb.update()
+}
+
+// renderRows will render the fill of each row of the progress bar. The
+// caller must hold r.mu.
+func (r *LineRenderer) renderRows(
+    s State, inProgress string, widths StyleWidths, available int,
+) []string {
+    if s.Indeterminate {
+        fills := []string{r.renderBounce(s, widths, available)}
+        for i := 1; i < s.Rows; i++ {
+            fills = append(fills, r.track(s, available))
+        }
+
+        return fills
+    }
+
+    if s.Rows <= 1 {
+        return []string{r.renderFill(s, inProgress, widths, available)}
+    }
+
+    rows := float64(s.Rows)
+    fill := s.Fill() * 100
+    active := int(math.Min(math.Floor(fill*rows/100), rows-1))
+
+    fills := make([]string, s.Rows)
+    for i := range fills {
+        // share will compute the part of a value drawn in this row.
+        offset := float64(i) * s.Max
+        share := func(value float64) float64 {
+            return math.Min(math.Max(value*rows-offset, 0), s.Max)
+        }
+
+        // Distance markers would mark the percentage of each row
+        // rather than that of the whole bar, so they are left out.
+        row := s
+        row.Scale = nil
+        row.Style.MarkerInterval = 0
+        row.Percent = math.Min(
+            math.Max(fill*rows-float64(i)*100, 0), 100)
+        row.Skipped = share(s.Skipped)
+        row.Failed = share(s.Value) - share(s.Value-s.Failed)
+
+        // Only the row the fill ends in shows the in-progress
+        // character.
+        rowInProgress := inProgress
+        if i > active {
+            rowInProgress = r.track(s, widths.InProgress)
+        }
+
+        fills[i] = r.renderFill(row, rowInProgress, widths, available)
+    }
+
+    return fills
+}
+
+// overwriteRows will prefix each row of a frame with clear and move
+// the cursor up to the first row of the frame drawn previously, so
+// that the frame is drawn over it. Rows left over from a taller frame
+// are cleared. The caller must hold pb.mu.
+func (pb *ProgressBar) overwriteRows(output, clear string) string {
+    rows := strings.Split(output, "\n")
+    for len(rows) < pb.drawnRows {
+        rows = append(rows, "")
+    }
+
+    var up string
+    if pb.drawnRows > 1 {
+        up = fmt.Sprintf("\033[%dA", pb.drawnRows-1)
+    }
+
+    pb.drawnRows = len(rows)
+    return up + clear + strings.Join(rows, "\n"+clear)
+}
@@ -0,0 +1,54 @@
+package progresscli
+
+import (
+    "math"
+)
+
+// Scale maps the fraction of the work that is complete, from 0 to 1,
+// to the fraction of the bar that is drawn filled. It allows the bar
+// to move nonlinearly for workloads where a linear bar misleads, for
+// example when most of the time is spent on the last few items. A
+// scale should map 0 to 0 and 1 to 1. Its results are constrained to
+// 0-1.
+type Scale func(fraction float64) float64
+
+// LogScale is a Scale that fills the bar logarithmically: quickly at
+// first, and more slowly as the work nears completion.
+func LogScale(fraction float64) float64 {
+    return math.Log10(1 + 9*fraction)
+}
+
+// SteppedScale will create a Scale that fills the bar in the specified
+// number of equal steps, only moving once each step is complete.
+func SteppedScale(steps int) Scale {
+    if steps < 1 {
+        steps = 1
+    }
+
+    return func(fraction float64) float64 {
+        return math.Floor(fraction*float64(steps)) / float64(steps)
+    }
+}
+
+// SetScale will set the Scale used to map the percentage of the
+// progress bar to the part of the bar that is drawn filled. The
+// displayed percentage and the decorators are not affected. Passing
+// nil fills the bar linearly, which is the default.
+func (pb *ProgressBar) SetScale(scale Scale) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    pb.scale = scale
+    pb.update()
+}
+
+// Fill will compute the fraction of the bar, from 0 to 1, that should
+// be drawn filled, by applying the Scale of the state to its Percent.
+func (s State) Fill() float64 {
+    fill := s.Percent / 100
+    if s.Scale != nil {
+        fill = s.Scale(fill)
+    }
+
+    return math.Min(math.Max(fill, 0), 1)
+}
@@ -0,0 +1,155 @@
+package progresscli
+
+import (
+    "sync"
+)
+
+// Scheduler controls when frames are drawn. Host applications with a
+// frame loop or rate limiter of their own can set a scheduler using
+// SetScheduler(), so that instead of drawing whenever a setter is
+// called, progress bars submit a function drawing their latest state,
+// which the scheduler runs whenever the host sees fit. Submit is
+// called while the progress bar is locked, so it must not run the
+// function before returning. Until the function runs, further changes
+// don't submit another one, and they are all drawn by it.
+type Scheduler interface {
+    Submit(render func())
+}
+
+// FrameScheduler is a Scheduler that queues the submitted functions
+// until Flush() is called, for example once per frame of the host
+// application's loop. You should initialize a new frame scheduler
+// using the NewFrameScheduler() function.
+type FrameScheduler struct {
+    mu    sync.Mutex
+    queue []func()
+}
+
+// NewFrameScheduler will create a new FrameScheduler with an empty
+// queue.
+func NewFrameScheduler() *FrameScheduler {
+    return &FrameScheduler{}
+}
+
+// Submit will add a function to the queue of the scheduler.
+func (s *FrameScheduler) Submit(render func()) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    s.queue = append(s.queue, render)
+}
+
+// Flush will run the queued functions, including the ones they submit
+// in turn, such as the redraw of a Manager after one of its progress
+// bars was drawn, and empty the queue.
+func (s *FrameScheduler) Flush() {
+    for {
+        s.mu.Lock()
+        queue := s.queue
+        s.queue = nil
+        s.mu.Unlock()
+
+        if len(queue) == 0 {
+            return
+        }
+
+        for _, render := range queue {
+            render()
+        }
+    }
+}
+
+// SetScheduler will set the Scheduler controlling when the progress
+// bar is drawn after it changes, is resized or its refresh interval
+// ticks. Lines printed using Println() and the newline written when
+// the progress bar is aborted are still written right away. Note that
+// the progress bar only finishes once its final frame has been drawn.
+// Passing nil restores drawing right away.
+func (pb *ProgressBar) SetScheduler(s Scheduler) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    pb.scheduler = s
+    pb.drawScheduled = false
+}
+
+// SetScheduler will set the Scheduler controlling when the manager
+// redraws its rows, for the manager and all of its progress bars,
+// including the ones added later. See ProgressBar.SetScheduler(). The
+// final redraw when the manager is stopped is done right away.
+func (m *Manager) SetScheduler(s Scheduler) {
+    m.mu.Lock()
+    m.scheduler = s
+    m.scheduled = false
+    bars := append([]*ProgressBar(nil), m.bars...)
+    m.mu.Unlock()
+
+    for _, pb := range bars {
+        pb.SetScheduler(s)
+    }
+}
+
+// requestDraw will draw the progress bar, or submit a function drawing
+// it to its scheduler if it has one and hasn't done so already. The
+// caller must hold pb.mu.
+func (pb *ProgressBar) requestDraw() {
+    if pb.scheduler == nil {
+        pb.draw()
+        return
+    }
+
+    if pb.drawScheduled {
+        return
+    }
+
+    pb.drawScheduled = true
+    pb.scheduler.Submit(pb.scheduledDraw)
+}
+
+// scheduledDraw will draw the progress bar when run by its scheduler,
+// unless it has stopped running in the meantime.
+func (pb *ProgressBar) scheduledDraw() {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+    defer pb.recoverRender()
+
+    if !pb.drawScheduled {
+        return
+    }
+
+    pb.drawScheduled = false
+    if pb.running() && !pb.suspend.active() {
+        pb.draw()
+    }
+}
+
+// requestDraw will redraw the manager's rows, or submit a function
+// redrawing them to its scheduler if it has one and hasn't done so
+// already. The caller must hold m.mu.
+func (m *Manager) requestDraw() {
+    if m.scheduler == nil {
+        m.draw()
+        return
+    }
+
+    if m.scheduled {
+        return
+    }
+
+    m.scheduled = true
+    m.scheduler.Submit(m.scheduledDraw)
+}
+
+// scheduledDraw will redraw the manager's rows when run by its
+// scheduler.
+func (m *Manager) scheduledDraw() {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    if !m.scheduled {
+        return
+    }
+
+    m.scheduled = false
+    m.draw()
+}
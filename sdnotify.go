@@ -0,0 +1,115 @@
+package progresscli
+
+import (
+    "fmt"
+    "net"
+    "os"
+    "strings"
+    "time"
+)
+
+// SetSystemdNotify will enable or disable reporting the progress of
+// the progress bar to systemd when the program runs as a service with
+// a NOTIFY_SOCKET, so that "systemctl status" shows live progress of
+// long startup or migration jobs. The label and whole percentage of
+// the progress bar are sent as the service's STATUS each time the
+// whole percentage changes, alongside rendering in the terminal. If
+// NOTIFY_SOCKET is not set, nothing is sent. Errors sending the status
+// are ignored, since they must not interrupt the job being tracked.
+func (pb *ProgressBar) SetSystemdNotify(enabled bool) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    pb.systemdNotify = enabled
+    pb.systemdStatus = ""
+    pb.notifySystemd(false)
+}
+
+// SetSystemdTimeoutExtension will ask systemd to extend the start,
+// runtime or stop timeout of the service by the specified duration
+// each time the progress bar reports progress, see SetSystemdNotify(),
+// so that a job which keeps making progress isn't killed for taking
+// longer than the configured timeout. Zero, the default, doesn't
+// extend the timeout.
+func (pb *ProgressBar) SetSystemdTimeoutExtension(d time.Duration) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    pb.systemdExtend = d
+}
+
+// statusReplacer replaces the line breaks in the STATUS sent to systemd
+// with spaces.
+var statusReplacer = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ")
+
+// notifySystemd will send the status of the progress bar to systemd
+// if it has changed since it was last sent. If final is true, the
+// status reports that the progress bar has finished or was aborted.
+// The caller must hold pb.mu.
+func (pb *ProgressBar) notifySystemd(final bool) {
+    if !pb.systemdNotify || !pb.visible {
+        return
+    }
+
+    // A newline would end the STATUS assignment and let the rest of
+    // the label be read as further assignments, such as READY=1.
+    status := statusReplacer.Replace(pb.progressText())
+    if final && pb.aborted {
+        status += " (aborted)"
+    }
+
+    if status == pb.systemdStatus {
+        return
+    }
+
+    state := "STATUS=" + status
+    if pb.systemdExtend > 0 && !final {
+        state += fmt.Sprintf(
+            "\nEXTEND_TIMEOUT_USEC=%d", pb.systemdExtend.Microseconds())
+    }
+
+    if sdNotify(state) == nil {
+        pb.systemdStatus = status
+    }
+}
+
+// progressText will describe the progress of the progress bar using
+// its label and whole percentage, e.g. "build 42%". The caller must
+// hold pb.mu.
+func (pb *ProgressBar) progressText() string {
+    percent := fmt.Sprintf("%.0f%%", pb.percent())
+    if pb.indeterminate() {
+        percent = "--%"
+    }
+
+    if pb.label == "" {
+        return percent
+    }
+
+    return pb.label + " " + percent
+}
+
+// sdNotify will send a notification to the service manager using the
+// socket in the NOTIFY_SOCKET environment variable, as described by
+// sd_notify(3). If the variable is not set, nothing is sent.
+func sdNotify(state string) error {
+    socket := os.Getenv("NOTIFY_SOCKET")
+    if socket == "" {
+        return nil
+    }
+
+    // Sockets in the abstract namespace are prefixed with an @.
+    if strings.HasPrefix(socket, "@") {
+        socket = "\x00" + socket[1:]
+    }
+
+    conn, err := net.DialUnix("unixgram", nil,
+                              &net.UnixAddr{Name: socket, Net: "unixgram"})
+    if err != nil {
+        return err
+    }
+    defer conn.Close()
+
+    _, err = conn.Write([]byte(state))
+    return err
+}
@@ -0,0 +1,56 @@
+package progresscli
+
+import (
+    "io"
+    "net"
+    "path/filepath"
+    "strings"
+    "testing"
+    "time"
+)
+
+// listenNotify will create a socket standing in for the one of the
+// service manager and point NOTIFY_SOCKET at it for the duration of
+// the test.
+func listenNotify(t *testing.T) *net.UnixConn {
+    t.Helper()
+
+    path := filepath.Join(t.TempDir(), "notify")
+    conn, err := net.ListenUnixgram("unixgram",
+                                    &net.UnixAddr{Name: path, Net: "unixgram"})
+    if err != nil {
+        t.Skipf("unixgram sockets are not available: %v", err)
+    }
+    t.Cleanup(func() { conn.Close() })
+
+    t.Setenv("NOTIFY_SOCKET", path)
+    return conn
+}
+
+func TestSystemdStatusWithoutNewlines(t *testing.T) {
+    conn := listenNotify(t)
+
+    pb := NewWithStyle(DefaultStyleNoColor())
+    pb.SetLabel("migrate\nREADY=1\r\nMAINPID=1")
+    pb.SetSystemdNotify(true)
+    pb.ShowIn(io.Discard)
+    defer pb.Abort()
+
+    conn.SetReadDeadline(time.Now().Add(time.Second))
+    buf := make([]byte, 4096)
+    n, err := conn.Read(buf)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    state := string(buf[:n])
+    want := "STATUS=migrate READY=1 MAINPID=1 0%"
+    if state != want {
+        t.Errorf("got %q, want %q", state, want)
+    }
+    for _, line := range strings.Split(state, "\n") {
+        if !strings.HasPrefix(line, "STATUS=") {
+            t.Errorf("%q was sent as a separate assignment", line)
+        }
+    }
+}
@@ -0,0 +1,189 @@
+package progresscli
+
+// SizeProvider supplies the size of the terminal progress bars are
+// drawn in. By default the size is detected using the consolesize
+// package, see the progresscli_minimal build tag. Embedders with a
+// terminal layer of their own, such as PTY multiplexers, web terminals
+// or tests, can supply the size directly.
+type SizeProvider interface {
+    // Size will retrieve the number of columns and rows of the
+    // terminal.
+    Size() (cols, rows int)
+}
+
+// ResizeNotifier is optionally implemented by a SizeProvider that
+// knows when the size of the terminal changes. Progress bars using
+// such a provider are redrawn right away when it does, instead of with
+// their next update.
+type ResizeNotifier interface {
+    // Resized will retrieve a channel receiving a value each time the
+    // size of the terminal changes. It is called once per provider.
+    Resized() <-chan struct{}
+}
+
+// SizeFunc is an adapter allowing an ordinary function to be used as a
+// SizeProvider.
+type SizeFunc func() (cols, rows int)
+
+// Size will call f.
+func (f SizeFunc) Size() (int, int) {
+    return f()
+}
+
+// SetSizeProvider will set the SizeProvider used to determine the size
+// of the terminal the progress bar is drawn in. Passing nil restores
+// the default. If the provider implements ResizeNotifier, the progress
+// bar is watched for resizes while it is running, until the channel is
+// closed or another provider is set.
+func (pb *ProgressBar) SetSizeProvider(p SizeProvider) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    pb.sizes = p
+    pb.unwatchResize()
+    pb.resized = nil
+    if n, ok := p.(ResizeNotifier); ok {
+        pb.resized = n.Resized()
+    }
+
+    if pb.running() {
+        pb.watchResize()
+    }
+
+    pb.update()
+}
+
+// watchResize will start the goroutine redrawing the progress bar when
+// the terminal is resized, if its size provider reports resizes and
+// the goroutine is not running yet. The caller must hold pb.mu.
+func (pb *ProgressBar) watchResize() {
+    if pb.resized == nil || pb.resizeDone != nil {
+        return
+    }
+
+    done := make(chan struct{})
+    pb.resizeDone = done
+    go watchResize(pb.resized, done, func() {
+        pb.redraw()
+    })
+}
+
+// unwatchResize will stop the goroutine started by watchResize(), if
+// it is running. The caller must hold pb.mu.
+func (pb *ProgressBar) unwatchResize() {
+    if pb.resizeDone != nil {
+        close(pb.resizeDone)
+        pb.resizeDone = nil
+    }
+}
+
+// SetSizeProvider will set the SizeProvider used to determine the size
+// of the terminal for all progress bars of the manager, including the
+// ones added later. If the provider implements ResizeNotifier, all
+// rows are redrawn when the terminal is resized, as long as the
+// manager is shown and any of its progress bars is running.
+func (m *Manager) SetSizeProvider(p SizeProvider) {
+    m.mu.Lock()
+    m.sizes = p
+    m.unwatchResize()
+    m.resized = nil
+    if n, ok := p.(ResizeNotifier); ok {
+        m.resized = n.Resized()
+    }
+    m.checkIdle()
+
+    bars := append([]*ProgressBar(nil), m.bars...)
+    m.mu.Unlock()
+
+    for _, pb := range bars {
+        pb.mu.Lock()
+        pb.sizes = p
+        pb.mu.Unlock()
+    }
+
+    m.redraw()
+}
+
+// terminalSize will retrieve the size of the terminal using the
+// progress bar's SizeProvider, falling back to the detected size if
+// it reports an invalid one. The caller must hold pb.mu.
+func (pb *ProgressBar) terminalSize() (int, int) {
+    if pb.sizes == nil {
+        return consoleSize()
+    }
+
+    cols, rows := pb.sizes.Size()
+    if cols <= 0 || rows <= 0 {
+        pb.reportError(ErrInvalidSize)
+        return consoleSize()
+    }
+
+    return cols, rows
+}
+
+// redraw will draw the progress bar again if it is visible.
+func (pb *ProgressBar) redraw() {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+    defer pb.recoverRender()
+
+    if pb.visible && !pb.finished && !pb.paused &&
+       !pb.suspend.active() {
+        pb.requestDraw()
+    }
+}
+
+// redraw will draw all progress bars of the manager again.
+func (m *Manager) redraw() {
+    m.mu.Lock()
+    bars := append([]*ProgressBar(nil), m.bars...)
+    m.mu.Unlock()
+
+    for _, pb := range bars {
+        pb.redraw()
+    }
+}
+
+// checkIdle will start the goroutine redrawing the manager's rows when
+// the terminal is resized if the manager is shown and any of its
+// progress bars is running, and stop it otherwise, so that an idle
+// manager doesn't keep a goroutine running. The caller must hold m.mu.
+func (m *Manager) checkIdle() {
+    if !m.visible || m.allEnded() {
+        m.unwatchResize()
+        return
+    }
+
+    if m.resized == nil || m.resizeDone != nil {
+        return
+    }
+
+    done := make(chan struct{})
+    m.resizeDone = done
+    go watchResize(m.resized, done, m.redraw)
+}
+
+// unwatchResize will stop the goroutine started by checkIdle(), if it
+// is running. The caller must hold m.mu.
+func (m *Manager) unwatchResize() {
+    if m.resizeDone != nil {
+        close(m.resizeDone)
+        m.resizeDone = nil
+    }
+}
+
+// watchResize will call redraw each time a value is received from
+// resized, until it is closed or done is closed.
+func watchResize(resized <-chan struct{}, done chan struct{}, redraw func()) {
+    for {
+        select {
+        case _, ok := <-resized:
+            if !ok {
+                return
+            }
+            redraw()
+        case <-done:
+            return
+        }
+    }
+}
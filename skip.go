@@ -0,0 +1,93 @@
+package progresscli
+
+import (
+    "fmt"
+    "strings"
+)
+
+// skipReason holds the number of items skipped for a reason.
+type skipReason struct {
+    reason string
+    count  float64
+}
+
+// Skip will advance the progress bar by the specified count while
+// recording the items as skipped rather than completed, for example
+// when a resumed job fast-forwards past items that were already
+// processed. Skipped items are grouped by reason in the Summary() of
+// the progress bar, and can be displayed as a separate segment of the
+// fill using the SkippedChar of the style.
+func (pb *ProgressBar) Skip(count float64, reason string) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    if pb.finished || !pb.visible {
+        return
+    }
+
+    pb.skipped += count
+    pb.addSkipReason(reason, count)
+    if pb.direction == Down {
+        pb.value -= count
+    } else {
+        pb.value += count
+    }
+
+    pb.update()
+}
+
+// Skipped will retrieve the number of items recorded as skipped using
+// Skip().
+func (pb *ProgressBar) Skipped() float64 {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    return pb.skipped
+}
+
+// Summary will describe the outcome of the work tracked by the
+// progress bar, for example
+// "150/150 processed, 30 skipped (cached: 20, up to date: 10), 3 failed".
+// Skip reasons are listed in the order they were first recorded.
+func (pb *ProgressBar) Summary() string {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    s := pb.state()
+    summary := fmt.Sprintf("%.0f/%.0f processed", s.done(), s.Max)
+
+    if pb.skipped > 0 {
+        summary += fmt.Sprintf(", %.0f skipped", pb.skipped)
+
+        var reasons []string
+        for _, r := range pb.skipReasons {
+            if r.reason != "" {
+                reasons = append(reasons,
+                    fmt.Sprintf("%s: %.0f", r.reason, r.count))
+            }
+        }
+
+        if len(reasons) > 0 {
+            summary += " (" + strings.Join(reasons, ", ") + ")"
+        }
+    }
+
+    if pb.failed > 0 {
+        summary += fmt.Sprintf(", %.0f failed", pb.failed)
+    }
+
+    return summary
+}
+
+// addSkipReason will add count to the number of items skipped for
+// reason. The caller must hold pb.mu.
+func (pb *ProgressBar) addSkipReason(reason string, count float64) {
+    for i := range pb.skipReasons {
+        if pb.skipReasons[i].reason == reason {
+            pb.skipReasons[i].count += count
+            return
+        }
+    }
+
+    pb.skipReasons = append(pb.skipReasons, skipReason{reason, count})
+}
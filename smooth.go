@@ -0,0 +1,103 @@
+package progresscli
+
+import (
+    "math"
+    "time"
+)
+
+const (
+    // smoothInterval is how often the displayed value of a progress bar
+    // using a moving average moves towards its value while the value
+    // doesn't change.
+    smoothInterval = 100 * time.Millisecond
+
+    // smoothThreshold is the distance, as a fraction of the max value,
+    // below which the moving average jumps to the value of the
+    // progress bar, so that it doesn't approach it forever.
+    smoothThreshold = 0.001
+)
+
+// Smoothing configures how the values of a progress bar are filtered
+// before they are displayed, for noisy sources such as polled file
+// sizes that occasionally report a lower value. Only what is drawn is
+// filtered: the value of the progress bar, and when it finishes, are
+// not affected.
+type Smoothing struct {
+    // Monotonic ignores values that move against the direction of the
+    // progress bar, so that the bar never visibly moves backwards.
+    Monotonic bool
+
+    // Factor is the weight, between 0 and 1, each new value has in an
+    // exponential moving average of the values, which evens out
+    // jitter at the cost of some lag. The average also moves towards
+    // the value on every tick, so that it catches up once the value
+    // stops changing. Zero, or one, displays each new value as it is.
+    Factor    float64
+}
+
+// SetSmoothing will set how the values of the progress bar are
+// filtered before they are displayed. The zero Smoothing, which is
+// the default, displays each value as it is.
+func (pb *ProgressBar) SetSmoothing(smoothing Smoothing) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    pb.stopTicker()
+    pb.smoothing = smoothing
+    pb.smoothed = pb.value
+    if pb.visible && !pb.finished {
+        pb.startTicker()
+    }
+    pb.update()
+}
+
+// smoothingEnabled will determine whether the displayed value is
+// filtered. The caller must hold pb.mu.
+func (pb *ProgressBar) smoothingEnabled() bool {
+    return pb.smoothing.Monotonic || pb.averaging()
+}
+
+// averaging will determine whether the displayed value is a moving
+// average of the values. The caller must hold pb.mu.
+func (pb *ProgressBar) averaging() bool {
+    return pb.smoothing.Factor > 0 && pb.smoothing.Factor < 1
+}
+
+// settle will move the displayed value of the progress bar towards its
+// value in response to a tick, reporting whether it moved. The caller
+// must hold pb.mu.
+func (pb *ProgressBar) settle() bool {
+    if !pb.averaging() {
+        return false
+    }
+
+    smoothed := pb.smoothed
+    pb.smooth()
+    return pb.smoothed != smoothed
+}
+
+// smooth will filter the current value of the progress bar into the
+// value that is displayed. The caller must hold pb.mu.
+func (pb *ProgressBar) smooth() {
+    if !pb.smoothingEnabled() {
+        return
+    }
+
+    value := pb.value
+    if pb.averaging() {
+        value = pb.smoothed + pb.smoothing.Factor*(value-pb.smoothed)
+        if math.Abs(pb.value-value) <= pb.max*smoothThreshold {
+            value = pb.value
+        }
+    }
+
+    if pb.smoothing.Monotonic {
+        if pb.direction == Down {
+            value = math.Min(value, pb.smoothed)
+        } else {
+            value = math.Max(value, pb.smoothed)
+        }
+    }
+
+    pb.smoothed = value
+}
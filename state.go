@@ -0,0 +1,248 @@
+package progresscli
+
+import (
+    "time"
+)
+
+// State is a snapshot of a progress bar taken each time a frame is
+// rendered. It is passed to decorators and renderers so they can
+// display information about the progress bar without accessing it
+// directly.
+type State struct {
+    // Value and Max are the current and maximum value of the progress
+    // bar.
+    Value   float64
+    Max     float64
+
+    // Failed is the number of items recorded as failed using
+    // IncrementFailed(). It is included in the value.
+    Failed  float64
+
+    // Skipped is the number of items recorded as skipped using
+    // Skip(). It is included in the value.
+    Skipped float64
+
+    // Percent is the percentage displayed by the progress bar.
+    Percent float64
+
+    // Scale maps Percent to the part of the bar drawn filled, see
+    // SetScale() and Fill(). It is nil if the bar is filled linearly.
+    Scale   Scale
+
+    // Indeterminate is true when the amount of work is unknown, see
+    // SetZeroMax(). Percent is zero and meaningless in that case.
+    Indeterminate bool
+
+    // Rate is the rate of change of the value in units per second,
+    // measured over the recent history of the progress bar's value.
+    // It is negative while a progress bar counting down progresses.
+    Rate    float64
+
+    // Cost is the cost accumulated using AddWeighted() and TotalCost
+    // the expected total cost set using SetTotalCost(). CostRate is
+    // the rate at which cost accumulates per second, measured like
+    // Rate. All three are zero unless AddWeighted() has been used.
+    Cost      float64
+    TotalCost float64
+    CostRate  float64
+
+    // Samples is the number of samples in the progress bar's history
+    // and Warmup the progress required before its ETA is estimated,
+    // see SetETAWarmup().
+    Samples int
+    Warmup  Warmup
+
+    // Direction is the direction in which the value moves.
+    Direction Direction
+
+    // Complete is true once the value has reached its end, which is
+    // the max value, or zero when counting down.
+    Complete bool
+
+    // Status is true for status lines created with NewStatus(), which
+    // display the activity of a long-lived process instead of a bar.
+    // LastActivity is the time Touch() was last called, or zero if it
+    // hasn't been, and Active is true shortly after.
+    Status       bool
+    LastActivity time.Time
+    Active       bool
+
+    // Counter is true for counters created with NewByteCounter(),
+    // which display a running count of bytes instead of a bar.
+    Counter      bool
+
+    // DryRun is true while the progress bar is in dry run mode, see
+    // SetDryRun(). Percent is zero in that case.
+    DryRun       bool
+
+    // Label and Verb are the label and verb of the progress bar. They
+    // are empty if none have been set.
+    Label   string
+    Verb    string
+
+    // RecentItems holds the items most recently set using
+    // SetCurrentItem(), oldest first. It is empty if none have been
+    // set.
+    RecentItems []string
+
+    // Style is the style of the progress bar and StyleWidths holds
+    // the visible widths of its components.
+    Style       Style
+    StyleWidths StyleWidths
+
+    // ShowPercentage and ShowPercentageDecimal tell the renderer
+    // whether, and with which precision, to display the percentage.
+    ShowPercentage        bool
+    ShowPercentageDecimal bool
+
+    // Width is the maximum width of the frame in columns.
+    Width   int
+
+    // MinBarWidth is the width the bar keeps when the frame doesn't
+    // fit in Width, see SetMinBarWidth().
+    MinBarWidth int
+
+    // Rows is the number of rows the bar wraps across, see SetRows().
+    // Renderers that only draw a single line may ignore it.
+    Rows    int
+
+    // Frame is the number of frames rendered before this one. It is
+    // used to animate spinners.
+    Frame   int
+
+    // Decorations holds the non-empty text produced by the progress
+    // bar's decorators, in order. It is always empty in the State
+    // passed to the decorators themselves.
+    Decorations []string
+
+    // Started is the time the progress bar was shown and Now is the
+    // time the frame was rendered, both according to the progress
+    // bar's clock.
+    Started time.Time
+    Now     time.Time
+
+    // TimeLocation and TimeFormat are the time zone and layout in
+    // which times of day are displayed, see FormatTime(). They are
+    // nil and empty unless set using SetTimeLocation() and
+    // SetTimeFormat().
+    TimeLocation *time.Location
+    TimeFormat   string
+}
+
+// Elapsed will retrieve the time that has passed since the progress
+// bar was shown.
+func (s State) Elapsed() time.Duration {
+    return s.Now.Sub(s.Started)
+}
+
+// ETA will estimate the time remaining until the progress bar is
+// complete, based on its recent rate of progress, or the average rate
+// since it was shown if there is no recent progress. For progress
+// bars incremented using AddWeighted(), the rate at which cost
+// accumulates is used instead. If no progress has been made yet, or
+// the warm-up set using SetETAWarmup() hasn't passed, false is
+// returned.
+func (s State) ETA() (time.Duration, bool) {
+    if s.Indeterminate || s.warmingUp() {
+        return 0, false
+    }
+
+    elapsed := s.Elapsed().Seconds()
+    done, remaining, rate := s.Value, s.Max-s.Value, s.Rate
+    if s.Direction == Down {
+        done, remaining, rate = s.Max-s.Value, s.Value, -s.Rate
+    }
+
+    if s.Cost > 0 {
+        return s.weightedETA(done, remaining)
+    }
+
+    if done <= 0 || elapsed <= 0 {
+        return 0, false
+    }
+
+    if rate <= 0 {
+        rate = done / elapsed
+    }
+
+    remaining = remaining / rate
+    return time.Duration(remaining * float64(time.Second)), true
+}
+
+// state will take a snapshot of the progress bar. The caller must
+// hold pb.mu.
+func (pb *ProgressBar) state() State {
+    s := State{
+        Value: pb.value,
+        Max: pb.max,
+        Failed: pb.failed,
+        Skipped: pb.skipped,
+        Percent: pb.percent(),
+        Scale: pb.scale,
+        Indeterminate: pb.indeterminate(),
+        Direction: pb.direction,
+        Complete: pb.complete(),
+        Status: pb.status,
+        Counter: pb.counter,
+        LastActivity: pb.touched,
+        Active: pb.active(),
+        DryRun: pb.dryRun,
+        Rate: pb.rate(),
+        Cost: pb.cost,
+        TotalCost: pb.totalCost,
+        CostRate: pb.costRate(),
+        Samples: pb.history.len(),
+        Warmup: pb.warmup,
+        Verb: pb.verb,
+        RecentItems: pb.items,
+        Rows: pb.rows,
+        Style: pb.style,
+        StyleWidths: pb.styleWidths,
+        ShowPercentage: pb.showPercentage,
+        ShowPercentageDecimal: pb.showPercentageDecimal,
+        Width: pb.maxWidth,
+        MinBarWidth: pb.minBarWidth,
+        Frame: pb.frame,
+        Started: pb.started,
+        Now: pb.now(),
+        TimeLocation: pb.timeLocation,
+        TimeFormat: pb.timeFormat,
+    }
+
+    if pb.showLabel {
+        s.Label = pb.label
+    }
+    if pb.labelWidth > 0 {
+        s.Label = fitWidth(s.Label, pb.labelWidth)
+    }
+
+    if pb.colorless() {
+        s.Style = pb.drawn.colorless
+        s.StyleWidths = pb.drawn.colorlessWidths
+    } else {
+        s.Style = pb.drawn.leveled
+        s.StyleWidths = pb.drawn.leveledWidths
+    }
+
+    if !s.Complete && pb.smoothingEnabled() {
+        s.Value = pb.smoothed
+        s.Percent = pb.percentOf(pb.smoothed)
+    }
+
+    if !s.Complete && pb.animating() {
+        s.Percent = pb.percentOf(pb.eased())
+    }
+
+    if s.DryRun {
+        s.Percent = 0
+    }
+
+    pb.adaptDecimals(&s)
+
+    if !pb.useCustomMaxWidth {
+        s.Width, _ = pb.terminalSize()
+    }
+    s.Width -= pb.affixWidth()
+
+    return s
+}
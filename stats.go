@@ -0,0 +1,255 @@
+package progresscli
+
+import (
+    "encoding/json"
+    "os"
+    "path/filepath"
+    "sync"
+    "time"
+)
+
+// DefaultAutoSaveInterval is the interval used by SetAutoSave() when
+// the specified interval is not positive.
+const DefaultAutoSaveInterval = time.Second
+
+// Stats is a record of the state of a progress bar that can be saved
+// to disk, for example to find out how far a job got after it
+// crashed, or to resume it from where it stopped.
+type Stats struct {
+    Label   string    `json:"label,omitempty"`
+    Value   float64   `json:"value"`
+    Max     float64   `json:"max"`
+    Percent float64   `json:"percent"`
+    Failed  float64   `json:"failed,omitempty"`
+    Skipped float64   `json:"skipped,omitempty"`
+    Rate    float64   `json:"rate"`
+    Phase   string    `json:"phase"`
+    Started time.Time `json:"started"`
+    Saved   time.Time `json:"saved"`
+}
+
+// Stats will retrieve the current stats of the progress bar.
+func (pb *ProgressBar) Stats() Stats {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    return pb.stats()
+}
+
+// SaveStats will write the current stats of the progress bar to the
+// specified file as JSON. The file is replaced atomically, so it
+// always holds a complete record even if the process is killed while
+// it is being written.
+func (pb *ProgressBar) SaveStats(path string) error {
+    pb.mu.Lock()
+    stats := pb.stats()
+    pb.mu.Unlock()
+
+    return writeStats(path, stats)
+}
+
+// SetAutoSave will periodically save the stats of the progress bar to
+// the specified file while it is updated, at most once per interval,
+// as well as once it finishes or is aborted. An interval that is not
+// positive is replaced with DefaultAutoSaveInterval. The periodic saves
+// are written in the background, so that updating the progress bar
+// never waits for the disk, while the final save is written before
+// the progress bar finishes. An empty path disables auto-saving.
+// Errors writing the file are ignored, since auto-saving must not
+// interrupt the job being tracked; use SaveStats() to handle them. To
+// save the stats somewhere other than a file, use SetAutoSaveStore().
+func (pb *ProgressBar) SetAutoSave(path string, interval time.Duration) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    if interval <= 0 {
+        interval = DefaultAutoSaveInterval
+    }
+
+    pb.autoSaveStore = nil
+    if path != "" {
+        pb.autoSaveStore = pathStore{}
+    }
+    pb.autoSaveKey = path
+    pb.autoSaveInterval = interval
+    pb.lastAutoSave = time.Time{}
+}
+
+// LoadStats will read stats previously saved to the specified file.
+func LoadStats(path string) (Stats, error) {
+    var stats Stats
+
+    data, err := os.ReadFile(path)
+    if err != nil {
+        return stats, err
+    }
+
+    err = json.Unmarshal(data, &stats)
+    return stats, err
+}
+
+// stats will take a record of the stats of the progress bar. The
+// caller must hold pb.mu.
+func (pb *ProgressBar) stats() Stats {
+    return Stats{
+        Label: pb.label,
+        Value: pb.value,
+        Max: pb.max,
+        Percent: pb.percent(),
+        Failed: pb.failed,
+        Skipped: pb.skipped,
+        Rate: pb.rate(),
+        Phase: pb.phase().String(),
+        Started: pb.started,
+        Saved: pb.now(),
+    }
+}
+
+// autoSave will save the stats of the progress bar if auto-saving is
+// enabled and the auto-save interval has passed since they were last
+// saved, or unconditionally if final is true. The caller must hold
+// pb.mu.
+func (pb *ProgressBar) autoSave(final bool) {
+    if pb.autoSaveStore == nil {
+        return
+    }
+
+    if !final && !pb.lastAutoSave.IsZero() &&
+       pb.since(pb.lastAutoSave) < pb.autoSaveInterval {
+        return
+    }
+
+    pb.lastAutoSave = pb.now()
+    save := autoSave{
+        store: pb.autoSaveStore,
+        key: pb.autoSaveKey,
+        stats: pb.stats(),
+    }
+
+    if final {
+        pb.autoSaver.save(save)
+    } else {
+        pb.autoSaver.queue(save)
+    }
+}
+
+// autoSave is a snapshot of the stats of a progress bar waiting to be
+// saved to a store.
+type autoSave struct {
+    store StateStore
+    key   string
+    stats Stats
+    seq   uint64
+}
+
+// autoSaver saves the stats of a progress bar in the background, so
+// that the progress bar isn't locked while they are written. Only the
+// latest snapshot waiting to be saved is kept, so that saves which
+// can't keep up with the updates of the progress bar are coalesced,
+// and a snapshot is never saved over a newer one.
+type autoSaver struct {
+    // mu protects the fields below. It is never held while saving.
+    mu      sync.Mutex
+    next    *autoSave
+    running bool
+    seq     uint64
+
+    // writing is held while saving, and saved is the sequence number
+    // of the newest snapshot saved.
+    writing sync.Mutex
+    saved   uint64
+}
+
+// queue will save the snapshot in the background, replacing any
+// snapshot still waiting to be saved.
+func (a *autoSaver) queue(save autoSave) {
+    a.mu.Lock()
+    defer a.mu.Unlock()
+
+    a.seq++
+    save.seq = a.seq
+    a.next = &save
+    if !a.running {
+        a.running = true
+        go a.run()
+    }
+}
+
+// save will save the snapshot right away, once any save in progress
+// has completed, and discard the older snapshot waiting to be saved.
+func (a *autoSaver) save(save autoSave) {
+    a.mu.Lock()
+    a.seq++
+    save.seq = a.seq
+    a.next = nil
+    a.mu.Unlock()
+
+    a.write(save)
+}
+
+// run will save the queued snapshots until there are none left.
+func (a *autoSaver) run() {
+    for {
+        a.mu.Lock()
+        next := a.next
+        a.next = nil
+        if next == nil {
+            a.running = false
+            a.mu.Unlock()
+            return
+        }
+        a.mu.Unlock()
+
+        a.write(*next)
+    }
+}
+
+// write will save the snapshot unless a newer one has been saved.
+func (a *autoSaver) write(save autoSave) {
+    a.writing.Lock()
+    defer a.writing.Unlock()
+
+    if save.seq <= a.saved {
+        return
+    }
+
+    a.saved = save.seq
+    save.store.Save(save.key, save.stats)
+}
+
+// writeStats will atomically replace the file at path with stats.
+func writeStats(path string, stats Stats) error {
+    data, err := json.MarshalIndent(stats, "", "  ")
+    if err != nil {
+        return err
+    }
+
+    return replaceFile(path, append(data, '\n'))
+}
+
+// replaceFile will atomically replace the file at path with data, by
+// writing it to a temporary file in the same directory and renaming
+// it.
+func replaceFile(path string, data []byte) error {
+    tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+    if err != nil {
+        return err
+    }
+    defer os.Remove(tmp.Name())
+
+    if _, err := tmp.Write(data); err != nil {
+        tmp.Close()
+        return err
+    }
+
+    if err := tmp.Sync(); err != nil {
+        tmp.Close()
+        return err
+    }
+
+    if err := tmp.Close(); err != nil {
+        return err
+    }
+
+    return os.Rename(tmp.Name(), path)
+}
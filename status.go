@@ -0,0 +1,79 @@
+package progresscli
+
+import (
+    "time"
+)
+
+// activityBlip is how long the activity indicator of a status line
+// stays lit after Touch() is called.
+const activityBlip = 500 * time.Millisecond
+
+// NewStatus will create a new progress bar in status mode, for
+// long-lived processes such as daemons that have no progress to
+// report. Instead of a bar, the status line displays the label, a
+// small activity indicator that lights up whenever Touch() is called
+// and the time of the last activity, followed by the decorations. The
+// status line never finishes on its own; call Abort(), or FinishAll()
+// on its manager, when the process stops.
+func NewStatus(label string) *ProgressBar {
+    pb := New()
+    pb.status = true
+    pb.label = label
+    pb.showLabel = strLen(label) > 0
+    pb.showPercentage = false
+    return pb
+}
+
+// Touch will record activity on a status line created with
+// NewStatus(), lighting up its activity indicator and updating the
+// time of the last activity.
+func (pb *ProgressBar) Touch() {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    pb.touched = pb.now()
+    pb.update()
+}
+
+// endless will determine whether the progress bar has no end value,
+// as is the case for status lines and gauges, so that it is only
+// complete once it has been stopped. The caller must hold pb.mu.
+func (pb *ProgressBar) endless() bool {
+    return pb.status || pb.gauge != nil
+}
+
+// blipChanged will determine whether the activity indicator of a
+// status line has been lit or gone out since the last frame was
+// rendered. The caller must hold pb.mu.
+func (pb *ProgressBar) blipChanged() bool {
+    return pb.status && pb.blipDrawn != pb.active()
+}
+
+// active will determine whether the activity indicator of a status
+// line is lit. The caller must hold pb.mu.
+func (pb *ProgressBar) active() bool {
+    return !pb.touched.IsZero() && pb.since(pb.touched) < activityBlip
+}
+
+// statusText will build the text displayed in place of the bar on a
+// status line: the activity indicator, using the done character of the
+// style while it is lit and the not-done character otherwise, and the
+// time of the last activity.
+func statusText(s State) string {
+    blip := s.Style.NotDoneChar
+    if s.Style.TrackColor != "" {
+        blip = s.Style.TrackColor + " \033[0m"
+    }
+    if s.Active {
+        blip = s.Style.DoneChar
+    }
+    if strLen(blip) == 0 {
+        blip = " "
+    }
+
+    if s.LastActivity.IsZero() {
+        return blip + " waiting for activity"
+    }
+
+    return blip + " last activity " + s.FormatTime(s.LastActivity)
+}
@@ -0,0 +1,181 @@
+package progresscli
+
+import (
+    "database/sql"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "io/fs"
+    "net/url"
+    "path/filepath"
+    "time"
+)
+
+// ErrNoStats is returned by a StateStore when no stats have been saved
+// under the requested key.
+var ErrNoStats = errors.New("progresscli: no stats saved under this key")
+
+// StateStore persists the stats of progress bars under a key, so that
+// applications can keep their progress checkpoints wherever they
+// already store job metadata. Load returns ErrNoStats if nothing has
+// been saved under the key. Auto-saves, see SetAutoSaveStore(), call
+// Save from a background goroutine rather than while the progress bar
+// is locked, and only with the latest stats, so a slow store delays
+// nothing but the next save.
+type StateStore interface {
+    Save(key string, stats Stats) error
+    Load(key string) (Stats, error)
+}
+
+// SaveStatsTo will save the current stats of the progress bar to the
+// specified store under the specified key.
+func (pb *ProgressBar) SaveStatsTo(store StateStore, key string) error {
+    pb.mu.Lock()
+    stats := pb.stats()
+    pb.mu.Unlock()
+
+    return store.Save(key, stats)
+}
+
+// SetAutoSaveStore will periodically save the stats of the progress
+// bar to the specified store under the specified key, in the same way
+// SetAutoSave() saves them to a file. The periodic saves run in the
+// background, one at a time, and stats that were replaced by newer
+// ones before they could be saved are skipped, so that a store such
+// as SQLStore runs at most one transaction per interval without ever
+// delaying the updates of the progress bar. A nil store disables
+// auto-saving.
+func (pb *ProgressBar) SetAutoSaveStore(store StateStore, key string, interval time.Duration) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    if interval <= 0 {
+        interval = DefaultAutoSaveInterval
+    }
+
+    pb.autoSaveStore = store
+    pb.autoSaveKey = key
+    pb.autoSaveInterval = interval
+    pb.lastAutoSave = time.Time{}
+}
+
+// pathStore is the StateStore used by SetAutoSave(), whose keys are
+// the paths of the files holding the stats.
+type pathStore struct{}
+
+func (pathStore) Save(path string, stats Stats) error {
+    return writeStats(path, stats)
+}
+
+func (pathStore) Load(path string) (Stats, error) {
+    stats, err := LoadStats(path)
+    if errors.Is(err, fs.ErrNotExist) {
+        return stats, ErrNoStats
+    }
+
+    return stats, err
+}
+
+// FileStore is a StateStore saving the stats under each key to a JSON
+// file named after the key in a directory, in the same format as
+// SaveStats(). You should initialize a new file store using the
+// NewFileStore() function.
+type FileStore struct {
+    dir string
+}
+
+// NewFileStore will create a new FileStore saving stats in the
+// specified directory, which must already exist.
+func NewFileStore(dir string) *FileStore {
+    return &FileStore{dir: dir}
+}
+
+// Save will atomically replace the file of the specified key with the
+// specified stats.
+func (s *FileStore) Save(key string, stats Stats) error {
+    return pathStore{}.Save(s.path(key), stats)
+}
+
+// Load will read the stats saved under the specified key.
+func (s *FileStore) Load(key string) (Stats, error) {
+    return pathStore{}.Load(s.path(key))
+}
+
+// path will retrieve the path of the file of the specified key. Every
+// character other than letters, digits and "-_.~" is escaped, so that
+// keys such as "a/job" and "b/job" are saved to different files in the
+// directory instead of the same one, or one outside of it.
+func (s *FileStore) path(key string) string {
+    return filepath.Join(s.dir, url.QueryEscape(key)+".json")
+}
+
+// SQLStore is a StateStore saving stats as JSON in a table of a
+// database/sql database, with one row per key. Its queries use ?
+// placeholders, as understood by the SQLite and MySQL drivers. You
+// should initialize a new SQL store using the NewSQLStore() function.
+type SQLStore struct {
+    db    *sql.DB
+    table string
+}
+
+// NewSQLStore will create a new SQLStore saving stats in the specified
+// table of the database, creating the table if it doesn't exist yet.
+// The table name is not escaped and must be trusted.
+func NewSQLStore(db *sql.DB, table string) (*SQLStore, error) {
+    _, err := db.Exec(fmt.Sprintf(
+        "CREATE TABLE IF NOT EXISTS %s (name VARCHAR(255) PRIMARY KEY, stats TEXT NOT NULL)",
+        table))
+    if err != nil {
+        return nil, err
+    }
+
+    return &SQLStore{db: db, table: table}, nil
+}
+
+// Save will replace the row of the specified key with the specified
+// stats in a single transaction. It waits for the database, so when
+// saving while a job runs, prefer SetAutoSaveStore(), which calls it
+// in the background.
+func (s *SQLStore) Save(key string, stats Stats) error {
+    data, err := json.Marshal(stats)
+    if err != nil {
+        return err
+    }
+
+    tx, err := s.db.Begin()
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    if _, err := tx.Exec(fmt.Sprintf(
+        "DELETE FROM %s WHERE name = ?", s.table), key); err != nil {
+        return err
+    }
+
+    if _, err := tx.Exec(fmt.Sprintf(
+        "INSERT INTO %s (name, stats) VALUES (?, ?)", s.table),
+        key, string(data)); err != nil {
+        return err
+    }
+
+    return tx.Commit()
+}
+
+// Load will read the stats saved under the specified key.
+func (s *SQLStore) Load(key string) (Stats, error) {
+    var stats Stats
+    var data string
+
+    err := s.db.QueryRow(fmt.Sprintf(
+        "SELECT stats FROM %s WHERE name = ?", s.table), key).Scan(&data)
+    if errors.Is(err, sql.ErrNoRows) {
+        return stats, ErrNoStats
+    }
+    if err != nil {
+        return stats, err
+    }
+
+    err = json.Unmarshal([]byte(data), &stats)
+    return stats, err
+}
@@ -0,0 +1,113 @@
+package progresscli
+
+// StyleWidths holds the visible widths, in columns, of the components
+// of a Style, with ANSI escape sequences removed. They are measured
+// once when the style is set rather than for every frame.
+type StyleWidths struct {
+    Open  int
+    Close int
+
+    // FinishedOpen and FinishedClose are the widths of the finished
+    // open and close characters, if the style has them.
+    FinishedOpen  int
+    FinishedClose int
+    Done          int
+    NotDone       int
+    Padding       int
+    Failed        int
+    Skipped       int
+
+    // InProgress is the width of the in-progress character, or of the
+    // widest spinner frame if the style has a spinner.
+    InProgress int
+}
+
+// SetStyle will set the style of the progress bar. If the progress bar
+// belongs to a Group, the style overrides that of the group.
+func (pb *ProgressBar) SetStyle(style Style) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    pb.style = style
+    pb.ownStyle = true
+    pb.restyle()
+    pb.update()
+}
+
+// GetStyle will retrieve the current style of the progress bar.
+func (pb *ProgressBar) GetStyle() Style {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    return pb.style
+}
+
+// StyleWidths will retrieve the measured widths of the components of
+// the progress bar's current style.
+func (pb *ProgressBar) StyleWidths() StyleWidths {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    return pb.styleWidths
+}
+
+// drawnStyle holds the variants of a progress bar's style it is drawn
+// with, adapted to its color level and without colors, along with their
+// widths. They are computed when the style or the color level changes
+// rather than for every frame.
+type drawnStyle struct {
+    leveled         Style
+    leveledWidths   StyleWidths
+    colorless       Style
+    colorlessWidths StyleWidths
+}
+
+// restyle will measure the style of the progress bar and compute the
+// variants it is drawn with. It must be called whenever the style or
+// the color level changes. The caller must hold pb.mu.
+func (pb *ProgressBar) restyle() {
+    pb.styleWidths = measureStyle(pb.style)
+
+    pb.drawn.leveled = pb.style
+    pb.drawn.leveledWidths = pb.styleWidths
+    if pb.colorLevel < TrueColor {
+        pb.drawn.leveled = pb.style.ForColorLevel(pb.colorLevel)
+        pb.drawn.leveledWidths = measureStyle(pb.drawn.leveled)
+    }
+
+    pb.drawn.colorless = pb.style.WithoutColor()
+    pb.drawn.colorlessWidths = measureStyle(pb.drawn.colorless)
+}
+
+// measureStyle will measure the visible widths of the components of a
+// style.
+func measureStyle(style Style) StyleWidths {
+    widths := StyleWidths{
+        Open: strLen(style.OpenChar),
+        Close: strLen(style.CloseChar),
+        FinishedOpen: strLen(style.FinishedOpenChar),
+        FinishedClose: strLen(style.FinishedCloseChar),
+        Done: strLen(style.DoneChar),
+        NotDone: strLen(style.NotDoneChar),
+        Padding: strLen(style.Padding),
+        Failed: strLen(style.FailedChar),
+        Skipped: strLen(style.SkippedChar),
+        InProgress: strLen(style.InProgressChar),
+    }
+
+    // With a track color, the not-done section is drawn using spaces.
+    if style.TrackColor != "" {
+        widths.NotDone = 1
+    }
+
+    if len(style.Spinner) > 0 {
+        widths.InProgress = 0
+        for _, frame := range style.Spinner {
+            if w := strLen(frame); w > widths.InProgress {
+                widths.InProgress = w
+            }
+        }
+    }
+
+    return widths
+}
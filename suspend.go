@@ -0,0 +1,130 @@
+package progresscli
+
+import (
+    "fmt"
+)
+
+// suspension guards the terminal while rendering is suspended, for
+// example while a prompt has put the terminal into raw mode. It counts
+// the calls to SuspendRendering() that have not been matched by a
+// call to ResumeRendering() yet, so that nested prompts only resume
+// rendering once the outermost one is done.
+type suspension int
+
+// suspend will record a call to SuspendRendering(), reporting whether
+// it suspended rendering.
+func (s *suspension) suspend() bool {
+    *s++
+    return *s == 1
+}
+
+// resume will record a call to ResumeRendering(), reporting whether
+// it resumed rendering.
+func (s *suspension) resume() bool {
+    if *s == 0 {
+        return false
+    }
+
+    *s--
+    return *s == 0
+}
+
+// active will report whether rendering is suspended.
+func (s suspension) active() bool {
+    return s > 0
+}
+
+// SuspendRendering will clear the progress bar and stop it from
+// writing to the terminal until ResumeRendering() is called, so that
+// a prompt, for example one reading a password with the terminal in
+// raw mode, can use the line the progress bar was drawn on without
+// escape sequences being written in the middle of it. Changes made in
+// the meantime are still recorded. Calls can be nested, and rendering
+// only resumes once each call has been matched by a call to
+// ResumeRendering(). If the progress bar belongs to a Manager, all of
+// the manager's progress bars are suspended.
+func (pb *ProgressBar) SuspendRendering() {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    if pb.manager != nil {
+        pb.manager.SuspendRendering()
+        return
+    }
+
+    if !pb.suspend.suspend() {
+        return
+    }
+
+    if !pb.visible || pb.finished || pb.pinned() {
+        return
+    }
+
+    switch pb.lineMode {
+    case Overwrite:
+        if pb.drawnRows > 1 {
+            fmt.Fprintf(pb.writer, "\033[%dA", pb.drawnRows-1)
+        }
+
+        fmt.Fprint(pb.writer, "\r\033[J")
+        pb.drawnRows = 0
+    case Backspace:
+        fmt.Fprint(pb.writer, pb.backspaceClear())
+    default:
+        return
+    }
+
+    if pb.autoFlush {
+        flush(pb.writer)
+    }
+}
+
+// ResumeRendering will resume rendering of a progress bar suspended
+// using SuspendRendering() and draw it on the current line.
+func (pb *ProgressBar) ResumeRendering() {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    if pb.manager != nil {
+        pb.manager.ResumeRendering()
+        return
+    }
+
+    if pb.suspend.resume() {
+        pb.update()
+    }
+}
+
+// SuspendRendering will clear the manager's progress bars and stop
+// them from writing to the terminal until ResumeRendering() is called.
+// See ProgressBar.SuspendRendering() for details.
+func (m *Manager) SuspendRendering() {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    if !m.suspend.suspend() || !m.visible || !m.inPlace() {
+        return
+    }
+
+    if m.lines > 1 {
+        fmt.Fprintf(m.writer, "\033[%dA", m.lines-1)
+    }
+
+    fmt.Fprint(m.writer, "\r\033[J")
+    m.lines = 0
+    m.drawn = nil
+    if m.autoFlush {
+        flush(m.writer)
+    }
+}
+
+// ResumeRendering will resume rendering of the manager's progress bars
+// and draw them starting on the current line.
+func (m *Manager) ResumeRendering() {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    if m.suspend.resume() {
+        m.draw()
+    }
+}
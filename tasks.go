@@ -0,0 +1,201 @@
+package progresscli
+
+import (
+    "fmt"
+    "io"
+    "os"
+    "sort"
+    "text/tabwriter"
+    "time"
+)
+
+// TaskResult describes how a job run by RunTasks(), RunParallel() or a
+// WorkerPool went.
+type TaskResult struct {
+    // Name is the name of the job.
+    Name     string
+
+    // Err is the error returned by the job, or nil if it succeeded.
+    Err      error
+
+    // Duration is the time the job took to run.
+    Duration time.Duration
+
+    // Value is the final value of the job's progress bar.
+    Value    float64
+
+    // index is the order in which the job was submitted.
+    index    int
+}
+
+// Rate will compute the average number of units processed by the job
+// per second. If the duration of the job is zero, 0 is returned.
+func (r TaskResult) Rate() float64 {
+    if r.Duration <= 0 {
+        return 0
+    }
+
+    return r.Value / r.Duration.Seconds()
+}
+
+// Results holds the results of several jobs in the order they were
+// submitted.
+type Results []TaskResult
+
+// Failed will count the jobs that returned an error.
+func (rs Results) Failed() int {
+    var failed int
+    for _, r := range rs {
+        if r.Err != nil {
+            failed++
+        }
+    }
+
+    return failed
+}
+
+// WriteTable will write an aligned table of the results to w, with
+// one row per job showing its name, duration, average rate and status,
+// followed by the distribution of the durations if there are several
+// jobs, see Durations(). For example:
+//
+//     TASK     DURATION  RATE   STATUS
+//     fetch    1.204s    830/s  ok
+//     compile  4.52s     -      failed: exit status 2
+//     2 jobs, p50 1.204s, p95 4.52s, max 4.52s ▁      █
+func (rs Results) WriteTable(w io.Writer) error {
+    tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+    fmt.Fprintln(tw, "TASK\tDURATION\tRATE\tSTATUS")
+
+    for _, r := range rs {
+        rate := "-"
+        if r.Value > 0 && r.Duration > 0 {
+            rate = formatNumber(r.Rate()) + "/s"
+        }
+
+        status := "ok"
+        if r.Err != nil {
+            status = "failed: " + r.Err.Error()
+        }
+
+        fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", ansi_re.ReplaceAllString(
+            r.Name, ""), r.Duration.Round(time.Millisecond), rate,
+            status)
+    }
+
+    if err := tw.Flush(); err != nil {
+        return err
+    }
+
+    if len(rs) < 2 {
+        return nil
+    }
+
+    _, err := fmt.Fprintln(w, rs.Durations())
+    return err
+}
+
+// PrintTable will print the table written by WriteTable() to STDOUT.
+func (rs Results) PrintTable() {
+    rs.WriteTable(os.Stdout)
+}
+
+// RunTasks will run the jobs one after the other, each with a progress
+// bar of its own shown using Show(). A job that fails doesn't stop
+// the jobs after it. The results of all jobs and the first error
+// returned by a job, if any, are returned. If a progress bar cannot be
+// shown, the results of the jobs run so far and the error returned by
+// Show() are returned. If a job panics, its progress bar is aborted
+// and the panic is passed on.
+func RunTasks(jobs ...Job) (Results, error) {
+    var (
+        results Results
+        first   error
+    )
+
+    for i, job := range jobs {
+        result, err := runTask(job, i)
+        if err != nil {
+            return results, err
+        }
+
+        if result.Err != nil && first == nil {
+            first = result.Err
+        }
+
+        results = append(results, result)
+    }
+
+    return results, first
+}
+
+// runTask will run a job of RunTasks() with a progress bar of its own.
+// The progress bar is finished when the job succeeds, and aborted when
+// it fails or panics.
+func runTask(job Job, index int) (TaskResult, error) {
+    pb := New()
+    pb.SetLabel(job.Name)
+    pb.SetZeroMax(ZeroMaxIndeterminate)
+    pb.SetMax(job.Size)
+    if err := pb.Show(); err != nil {
+        return TaskResult{}, err
+    }
+
+    failed := true
+    defer func() {
+        if failed {
+            pb.Abort()
+        }
+    }()
+
+    result := runJob(pb, job, index)
+    if result.Err != nil {
+        return result, nil
+    }
+
+    failed = false
+
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    pb.finish()
+    return result, nil
+}
+
+// RunParallel will run the jobs using a WorkerPool with the specified
+// number of workers, shown using Show(). The results of all jobs, in the
+// order they were passed, and the first error returned by a job, if
+// any, are returned.
+func RunParallel(workers int, jobs ...Job) (Results, error) {
+    p := NewWorkerPool(workers)
+    for _, job := range jobs {
+        p.Submit(job)
+    }
+
+    p.Show()
+    err := p.Wait()
+    return p.Results(), err
+}
+
+// runJob will run a job using the specified progress bar, measuring
+// how long it takes.
+func runJob(pb *ProgressBar, job Job, index int) TaskResult {
+    start := time.Now()
+    err := job.Run(pb)
+
+    return TaskResult{
+        Name: job.Name,
+        Err: err,
+        Duration: time.Since(start),
+        Value: pb.GetValue(),
+        index: index,
+    }
+}
+
+// sortResults will sort results into the order their jobs were
+// submitted in.
+func sortResults(rs Results) {
+    sort.SliceStable(rs, func(i, j int) bool {
+        return rs[i].index < rs[j].index
+    })
+}
@@ -0,0 +1,44 @@
+// Package termenvcolor adapts the color profile of
+// github.com/muesli/termenv for use with progresscli.
+package termenvcolor
+
+import (
+    "github.com/muesli/termenv"
+    "github.com/nathan-fiscaletti/progresscli-go"
+)
+
+// Provider is a progresscli.ColorProvider that follows the color
+// profile of a termenv.Output. Colors are disabled if the profile is
+// termenv.Ascii, for example because the output is not a terminal or
+// NO_COLOR is set. You should initialize a new provider using the
+// New() function.
+type Provider struct {
+    output *termenv.Output
+}
+
+// New will create a new Provider for the specified output. If output
+// is nil, termenv.DefaultOutput() is used, so that changes made with
+// termenv.SetDefaultOutput() are followed.
+func New(output *termenv.Output) Provider {
+    return Provider{output: output}
+}
+
+// ColorEnabled will determine whether the color profile of the output
+// supports colors. The profile is the one the output was created with,
+// which termenv.NewOutput() detects honoring NO_COLOR and CLICOLOR, or
+// the one set on it since, so the terminal isn't queried on every
+// frame.
+func (p Provider) ColorEnabled() bool {
+    output := p.output
+    if output == nil {
+        output = termenv.DefaultOutput()
+    }
+
+    return output.Profile != termenv.Ascii
+}
+
+// Apply will make the progress bar follow the color profile of
+// termenv's default output.
+func Apply(pb *progresscli.ProgressBar) {
+    pb.SetColorProvider(New(nil))
+}
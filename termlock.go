@@ -0,0 +1,137 @@
+package progresscli
+
+import (
+    "fmt"
+    "io"
+    "os"
+    "path/filepath"
+    "time"
+)
+
+// terminalLockPoll is the interval at which a progress bar waiting for
+// the terminal lock tries to acquire it.
+const terminalLockPoll = 100 * time.Millisecond
+
+// SetTerminalLock will enable or disable an advisory lock that lets
+// several processes using this package share a terminal, for example
+// when they are launched side by side by a common parent. The lock is
+// a file in the temporary directory named after the terminal the
+// progress bar is shown in, so processes writing to other terminals
+// are not affected. Only one progress bar holds the lock at a time.
+// While another one holds it, a progress bar records its changes
+// without drawing anything, and it is drawn once the other progress
+// bar finishes or is aborted. The lock is released when the progress
+// bar finishes or is aborted. Writers that are not terminals, and
+// platforms without file locks, are never locked.
+//
+// Changes take effect the next time the progress bar is shown.
+func (pb *ProgressBar) SetTerminalLock(enabled bool) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    pb.terminalLock = enabled
+}
+
+// lockTerminal will acquire the terminal lock of the progress bar, if
+// it is enabled. If another process holds the lock, rendering is
+// suspended until it is released. The caller must hold pb.mu.
+func (pb *ProgressBar) lockTerminal() {
+    if !pb.terminalLock || pb.lockFile != nil || pb.manager != nil {
+        return
+    }
+
+    path, ok := terminalLockPath(pb.writer)
+    if !ok {
+        return
+    }
+
+    f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o600)
+    if err != nil {
+        return
+    }
+
+    pb.lockFile = f
+    if lockFile(f) == nil {
+        pb.lockHeld = true
+        return
+    }
+
+    pb.lockDone = make(chan struct{})
+    pb.suspend.suspend()
+    go pb.awaitTerminal(f, pb.lockDone)
+}
+
+// awaitTerminal will wait for the terminal lock to be released by
+// another process and resume rendering of the progress bar once it
+// has been acquired. Instead of blocking until the lock is released,
+// which can't be interrupted, it tries to acquire it periodically, so
+// that it stops waiting as soon as done is closed because the progress
+// bar finished or was aborted.
+func (pb *ProgressBar) awaitTerminal(f *os.File, done chan struct{}) {
+    ticker := time.NewTicker(terminalLockPoll)
+    defer ticker.Stop()
+
+    for lockFile(f) != nil {
+        select {
+        case <-done:
+            f.Close()
+            return
+        case <-ticker.C:
+        }
+    }
+
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+    defer pb.recoverRender()
+
+    // The progress bar was finished or aborted in the meantime.
+    if pb.lockFile != f {
+        f.Close()
+        return
+    }
+
+    pb.lockHeld = true
+    if pb.suspend.resume() {
+        pb.update()
+    }
+}
+
+// unlockTerminal will release the terminal lock of the progress bar,
+// or stop waiting for it. The caller must hold pb.mu.
+func (pb *ProgressBar) unlockTerminal() {
+    if pb.lockFile == nil {
+        return
+    }
+
+    // A goroutine waiting for the lock closes the file itself once it
+    // stops waiting.
+    if pb.lockHeld {
+        pb.lockFile.Close()
+    } else {
+        close(pb.lockDone)
+        pb.suspend.resume()
+    }
+
+    pb.lockFile = nil
+    pb.lockDone = nil
+    pb.lockHeld = false
+}
+
+// terminalLockPath will build the path of the lock file for the
+// terminal the specified writer writes to. Writers that are not files
+// are assumed to wrap STDOUT. If the writer is not a terminal, false
+// is returned.
+func terminalLockPath(w io.Writer) (string, bool) {
+    f, ok := w.(*os.File)
+    if !ok {
+        f = os.Stdout
+    }
+
+    key, ok := terminalKey(f)
+    if !ok {
+        return "", false
+    }
+
+    name := fmt.Sprintf("progresscli-%s.lock", key)
+    return filepath.Join(os.TempDir(), name), true
+}
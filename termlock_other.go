@@ -0,0 +1,19 @@
+//go:build !darwin && !dragonfly && !freebsd && !illumos && !linux && !netbsd && !openbsd
+
+package progresscli
+
+import (
+    "os"
+)
+
+// lockFile will do nothing, as flock() is not available on this
+// platform, which includes Windows, Solaris and AIX.
+func lockFile(f *os.File) error {
+    return nil
+}
+
+// terminalKey will report that the specified file is not a terminal,
+// so that the terminal lock is never used on this platform.
+func terminalKey(f *os.File) (string, bool) {
+    return "", false
+}
@@ -0,0 +1,33 @@
+//go:build darwin || dragonfly || freebsd || illumos || linux || netbsd || openbsd
+
+package progresscli
+
+import (
+    "os"
+    "strconv"
+    "syscall"
+)
+
+// lockFile will place an exclusive advisory lock on the specified
+// file. If the file is already locked, an error is returned instead
+// of waiting for the lock to be released.
+func lockFile(f *os.File) error {
+    return syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+}
+
+// terminalKey will identify the terminal the specified file refers to
+// using its device number. If the file is not a terminal, false is
+// returned.
+func terminalKey(f *os.File) (string, bool) {
+    info, err := f.Stat()
+    if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+        return "", false
+    }
+
+    stat, ok := info.Sys().(*syscall.Stat_t)
+    if !ok {
+        return "", false
+    }
+
+    return strconv.FormatUint(uint64(stat.Rdev), 16), true
+}
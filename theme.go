@@ -0,0 +1,114 @@
+package progresscli
+
+import (
+    "os"
+    "strconv"
+    "strings"
+)
+
+// Background is the brightness of the terminal background, which
+// determines which colors remain readable on it.
+type Background int
+
+const (
+    // DarkBackground is a dark terminal background. The built in
+    // styles are designed for dark backgrounds.
+    DarkBackground Background = iota
+
+    // LightBackground is a light terminal background, on which the
+    // bright white used by the built in styles is barely visible.
+    LightBackground
+)
+
+// lightReplacer replaces the colors of the built in styles that are
+// unreadable on a light background with darker variants.
+var lightReplacer = strings.NewReplacer(
+    "\033[1;37m", "\033[90m",
+    "\033[1;32m", "\033[32m",
+    "\033[1;33m", "\033[33m",
+    "\033[48;5;237m", "\033[48;5;253m",
+)
+
+// DetectBackground will guess the brightness of the terminal
+// background from the COLORFGBG environment variable set by terminals
+// such as rxvt, Konsole and iTerm2. If the variable is not set, the
+// background is assumed to be dark.
+func DetectBackground() Background {
+    value := os.Getenv("COLORFGBG")
+    if i := strings.LastIndex(value, ";"); i >= 0 {
+        value = value[i+1:]
+    }
+
+    bg, err := strconv.Atoi(value)
+    if err != nil {
+        return DarkBackground
+    }
+
+    // Of the 16 basic colors, white (7) and the bright colors other
+    // than bright black (8) are light.
+    if bg == 7 || (bg >= 9 && bg <= 15) {
+        return LightBackground
+    }
+
+    return DarkBackground
+}
+
+// ForBackground will retrieve a variant of the style that is readable
+// on the specified background. For a light background, the bright
+// colors used by the built in styles are replaced with darker ones.
+// Other colors are kept as they are.
+func (s Style) ForBackground(bg Background) Style {
+    if bg != LightBackground {
+        return s
+    }
+
+    return s.mapSequences(lightReplacer.Replace)
+}
+
+// mapSequences will apply f to every field of the style that can hold
+// escape sequences.
+func (s Style) mapSequences(f func(string) string) Style {
+    s.OpenChar = f(s.OpenChar)
+    s.CloseChar = f(s.CloseChar)
+    s.FinishedOpenChar = f(s.FinishedOpenChar)
+    s.FinishedCloseChar = f(s.FinishedCloseChar)
+    s.DoneChar = f(s.DoneChar)
+    s.NotDoneChar = f(s.NotDoneChar)
+    s.InProgressChar = f(s.InProgressChar)
+    s.FailedChar = f(s.FailedChar)
+    s.SkippedChar = f(s.SkippedChar)
+    s.TrackColor = f(s.TrackColor)
+    s.MarkerChar = f(s.MarkerChar)
+    s.Padding = f(s.Padding)
+    s.PercentageColor = f(s.PercentageColor)
+    s.VerbColor = f(s.VerbColor)
+
+    spinner := make([]string, len(s.Spinner))
+    for i, frame := range s.Spinner {
+        spinner[i] = f(frame)
+    }
+    if len(spinner) > 0 {
+        s.Spinner = spinner
+    }
+
+    return s
+}
+
+// DefaultStyleLight will retrieve the default Style for progress bars
+// with colors suited to a light terminal background.
+func DefaultStyleLight() Style {
+    return DefaultStyle().ForBackground(LightBackground)
+}
+
+// LineStyleLight will retrieve a line type Style for progress bars
+// with colors suited to a light terminal background.
+func LineStyleLight() Style {
+    return LineStyle().ForBackground(LightBackground)
+}
+
+// MinimalStyleLight will retrieve a variant of MinimalStyle() drawing
+// the band over a light gray track, suited to a light terminal
+// background.
+func MinimalStyleLight() Style {
+    return MinimalStyle().ForBackground(LightBackground)
+}
@@ -0,0 +1,127 @@
+package progresscli
+
+import (
+    "fmt"
+    "time"
+)
+
+// Throughput is the combined progress of the progress bars of a
+// Manager, see Manager.Throughput().
+type Throughput struct {
+    // Active is the number of progress bars that have neither
+    // finished nor been aborted.
+    Active int
+
+    // Rate is the combined rate of progress of the progress bars in
+    // units per second.
+    Rate   float64
+}
+
+// throughput is the rate bookkeeping shared by the progress bars of a
+// Manager. Instead of adding up the rates of the individual progress
+// bars, which each average over a different period, the progress made
+// by any of them is added to a single running total, whose history is
+// kept like that of a progress bar.
+type throughput struct {
+    seen    map[*ProgressBar]float64
+    total   float64
+    history *sampleRing
+    now     time.Time
+}
+
+func newThroughput() throughput {
+    return throughput{
+        seen: map[*ProgressBar]float64{},
+        history: newSampleRing(DefaultHistoryCapacity),
+    }
+}
+
+// observe will add the progress made by a progress bar since it was
+// last observed to the running total. The caller must hold pb.mu.
+func (t *throughput) observe(pb *ProgressBar) {
+    done := pb.value
+    if pb.direction == Down {
+        done = pb.max - pb.value
+    }
+
+    t.now = pb.now()
+    last, ok := t.seen[pb]
+    t.seen[pb] = done
+    if !ok || done <= last {
+        return
+    }
+
+    t.total += done - last
+    t.history.push(sample{at: t.now, value: t.total})
+}
+
+// forget will stop tracking a progress bar. The progress it made is
+// kept in the running total.
+func (t *throughput) forget(pb *ProgressBar) {
+    delete(t.seen, pb)
+}
+
+// reset will clear the history of the running total.
+func (t *throughput) reset() {
+    t.history.reset()
+}
+
+// rate will compute the rate at which the running total grows in units
+// per second over its history.
+func (t *throughput) rate() float64 {
+    if t.history.len() == 0 {
+        return 0
+    }
+
+    oldest := t.history.oldest()
+    elapsed := t.now.Sub(oldest.at).Seconds()
+    if elapsed <= 0 {
+        return 0
+    }
+
+    return (t.total - oldest.value) / elapsed
+}
+
+// SetSummaryRow will display a row below the manager's progress bars
+// showing their combined throughput, formatted by the specified
+// function, for example ByteRateSummary(). Passing nil removes the
+// row.
+func (m *Manager) SetSummaryRow(format func(Throughput) string) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    m.summaryRow = format
+    m.requestDraw()
+}
+
+// Throughput will retrieve the combined rate of progress of the
+// manager's progress bars, e.g. the total number of bytes per second
+// transferred by several concurrent downloads.
+func (m *Manager) Throughput() Throughput {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    return m.currentThroughput()
+}
+
+// currentThroughput will retrieve the combined rate of progress of the
+// manager's progress bars. The caller must hold m.mu.
+func (m *Manager) currentThroughput() Throughput {
+    t := Throughput{Rate: m.throughput.rate()}
+    for _, ended := range m.ends {
+        if !ended {
+            t.Active++
+        }
+    }
+
+    return t
+}
+
+// ByteRateSummary will create a function for SetSummaryRow() that
+// displays the number of active progress bars and their combined rate
+// in bytes per second, for example "3 active, 12.5 MiB/s".
+func ByteRateSummary() func(Throughput) string {
+    return func(t Throughput) string {
+        return fmt.Sprintf("%d active, %s/s", t.Active, formatBytes(t.Rate))
+    }
+}
@@ -0,0 +1,71 @@
+package progresscli
+
+import (
+    "time"
+)
+
+// DefaultTimeFormat is the layout used to display times of day, such
+// as the time of the last activity on a status line, unless another
+// one is set using SetTimeFormat().
+const DefaultTimeFormat = "15:04:05"
+
+// SetTimeLocation will set the time zone in which times of day are
+// displayed, such as by CompletionTimeDecorator(). Passing nil uses
+// the local time zone, which is the default.
+func (pb *ProgressBar) SetTimeLocation(loc *time.Location) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    pb.timeLocation = loc
+    pb.update()
+}
+
+// SetTimeFormat will set the layout, as used by time.Time.Format(), in
+// which times of day are displayed, such as by CompletionTimeDecorator().
+// An empty layout uses DefaultTimeFormat.
+func (pb *ProgressBar) SetTimeFormat(layout string) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    pb.timeFormat = layout
+    pb.update()
+}
+
+// FormatTime will format a time of day in the time zone and layout
+// of the state, see SetTimeLocation() and SetTimeFormat().
+func (s State) FormatTime(t time.Time) string {
+    loc := s.TimeLocation
+    if loc == nil {
+        loc = time.Local
+    }
+
+    layout := s.TimeFormat
+    if layout == "" {
+        layout = DefaultTimeFormat
+    }
+
+    return t.In(loc).Format(layout)
+}
+
+// StartedAtDecorator will create a decorator that displays the time
+// of day the progress bar was shown, for example "started 14:02:11".
+func StartedAtDecorator() Decorator {
+    return func(s State) string {
+        return "started " + s.FormatTime(s.Started)
+    }
+}
+
+// CompletionTimeDecorator will create a decorator that displays the
+// estimated time of day the progress bar will be complete, for example
+// "done by 14:32:05", which is often easier to plan around than the
+// remaining time displayed by ETADecorator().
+func CompletionTimeDecorator() Decorator {
+    return func(s State) string {
+        eta, ok := s.ETA()
+        if !ok {
+            return "done by --:--"
+        }
+
+        return "done by " + s.FormatTime(s.Now.Add(eta))
+    }
+}
@@ -0,0 +1,120 @@
+package progresscli
+
+import (
+    "io"
+    "strconv"
+)
+
+// Number is the set of numeric types a Tracker can count in.
+type Number interface {
+    ~int | ~int8 | ~int16 | ~int32 | ~int64 |
+    ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+    ~float32 | ~float64
+}
+
+// Tracker is a typed facade over a ProgressBar, so that callers
+// counting in integers or other numeric types don't have to convert
+// every value to float64. Values are displayed without decimals if T
+// is an integer type. You should initialize a new tracker using the
+// NewTracker() function.
+type Tracker[T Number] struct {
+    bar *ProgressBar
+}
+
+// NewTracker will create a new Tracker with the specified max value,
+// using a progress bar with the default style.
+func NewTracker[T Number](max T) *Tracker[T] {
+    t := &Tracker[T]{bar: New()}
+    t.bar.SetMax(float64(max))
+    return t
+}
+
+// Bar will retrieve the progress bar of the tracker, so that its
+// style, label and decorators can be customized.
+func (t *Tracker[T]) Bar() *ProgressBar {
+    return t.bar
+}
+
+// Show will show the tracker's progress bar in the writer retrieved
+// by DefaultWriter().
+func (t *Tracker[T]) Show() error {
+    return t.bar.ShowIn(DefaultWriter())
+}
+
+// ShowIn will show the tracker's progress bar in the specified
+// io.Writer.
+func (t *Tracker[T]) ShowIn(w io.Writer) error {
+    return t.bar.ShowIn(w)
+}
+
+// Add will increment the value of the tracker by n.
+func (t *Tracker[T]) Add(n T) {
+    t.bar.Increment(float64(n))
+}
+
+// Fail will increment the value of the tracker by n, recording the
+// items as failed. See ProgressBar.IncrementFailed().
+func (t *Tracker[T]) Fail(n T) {
+    t.bar.IncrementFailed(float64(n))
+}
+
+// Skip will increment the value of the tracker by n, recording the
+// items as skipped. See ProgressBar.Skip().
+func (t *Tracker[T]) Skip(n T, reason string) {
+    t.bar.Skip(float64(n), reason)
+}
+
+// Set will set the value of the tracker.
+func (t *Tracker[T]) Set(value T) {
+    t.bar.SetValue(float64(value))
+}
+
+// Value will retrieve the value of the tracker.
+func (t *Tracker[T]) Value() T {
+    return T(t.bar.GetValue())
+}
+
+// SetMax will set the max value of the tracker. See
+// ProgressBar.SetMax() for the values that are accepted.
+func (t *Tracker[T]) SetMax(max T) error {
+    return t.bar.SetMax(float64(max))
+}
+
+// Max will retrieve the max value of the tracker.
+func (t *Tracker[T]) Max() T {
+    return T(t.bar.GetMax())
+}
+
+// Failed will retrieve the number of items recorded as failed.
+func (t *Tracker[T]) Failed() T {
+    return T(t.bar.Failed())
+}
+
+// Skipped will retrieve the number of items recorded as skipped.
+func (t *Tracker[T]) Skipped() T {
+    return T(t.bar.Skipped())
+}
+
+// Format will format a value of the tracker, without decimals if T is
+// an integer type.
+func (t *Tracker[T]) Format(v T) string {
+    if isInteger[T]() {
+        return strconv.FormatFloat(float64(v), 'f', 0, 64)
+    }
+
+    return strconv.FormatFloat(float64(v), 'f', -1, 64)
+}
+
+// ValueDecorator will create a decorator displaying the value and max
+// value of the tracker, for example "120/500".
+func (t *Tracker[T]) ValueDecorator() Decorator {
+    return func(s State) string {
+        return t.Format(T(s.Value)) + "/" + t.Format(T(s.Max))
+    }
+}
+
+// isInteger will determine whether T is an integer type.
+func isInteger[T Number]() bool {
+    var one T = 1
+    return one/2 == 0
+}
@@ -0,0 +1,114 @@
+package progresscli
+
+import (
+    "fmt"
+    "io"
+    "sync/atomic"
+)
+
+// Transfer is a two row display for copying or downloading a set of
+// files, similar to the one used by rsync. The top row shows the
+// overall number of bytes and files transferred along with an
+// estimated time remaining, and the bottom row shows the progress of
+// the file currently being transferred. You should initialize a new
+// transfer using the NewTransfer() function.
+type Transfer struct {
+    manager    *Manager
+    overall    *ProgressBar
+    file       *ProgressBar
+    files      int64
+    totalFiles int
+}
+
+// NewTransfer will create a new Transfer for the specified total
+// number of bytes and files.
+func NewTransfer(totalBytes float64, totalFiles int) *Transfer {
+    t := &Transfer{
+        manager: NewManager(),
+        overall: New(),
+        file: New(),
+        totalFiles: totalFiles,
+    }
+
+    t.overall.SetMax(totalBytes)
+    t.overall.AddDecorator(BytesDecorator())
+    t.overall.AddDecorator(t.filesDecorator)
+    t.overall.AddDecorator(ETADecorator())
+    t.file.AddDecorator(BytesDecorator())
+
+    t.manager.Add(t.overall)
+    t.manager.Add(t.file)
+    return t
+}
+
+// Overall will retrieve the progress bar used for the top row, so
+// that its style and decorators can be customized.
+func (t *Transfer) Overall() *ProgressBar {
+    return t.overall
+}
+
+// File will retrieve the progress bar used for the bottom row, so
+// that its style and decorators can be customized.
+func (t *Transfer) File() *ProgressBar {
+    return t.file
+}
+
+// Show will show the transfer in the writer retrieved by
+// DefaultWriter().
+func (t *Transfer) Show() {
+    t.ShowIn(DefaultWriter())
+}
+
+// ShowIn will show the transfer in the specified io.Writer.
+func (t *Transfer) ShowIn(w io.Writer) {
+    t.manager.ShowIn(w)
+}
+
+// StartFile will begin the transfer of a new file with the specified
+// name and size in bytes. The bottom row is reset to display the
+// progress of the new file. If size is negative, NaN or infinite,
+// ErrInvalidMax is returned and the file is not started.
+func (t *Transfer) StartFile(name string, size float64) error {
+    if !validMax(size) {
+        return ErrInvalidMax
+    }
+
+    atomic.AddInt64(&t.files, 1)
+
+    pb := t.file
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    pb.label = name
+    pb.showLabel = strLen(name) > 0
+    pb.max = size
+    pb.reset()
+
+    // The row starts running once the transfer is shown.
+    if !pb.visible {
+        pb.finished = false
+        pb.aborted = false
+        return nil
+    }
+
+    pb.begin()
+    return nil
+}
+
+// AddBytes will add n bytes to both the current file and the overall
+// transfer.
+func (t *Transfer) AddBytes(n float64) {
+    t.file.Increment(n)
+    t.overall.Increment(n)
+}
+
+// Finish will draw the final state of the transfer and move the
+// cursor below it.
+func (t *Transfer) Finish() {
+    t.manager.Stop()
+}
+
+func (t *Transfer) filesDecorator(s State) string {
+    files := atomic.LoadInt64(&t.files)
+    return fmt.Sprintf("%d/%d files", files, t.totalFiles)
+}
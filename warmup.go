@@ -0,0 +1,38 @@
+package progresscli
+
+import (
+    "time"
+)
+
+// Warmup is the minimum amount of progress a progress bar must have
+// made before its ETA is displayed, as the first few samples of its
+// rate tend to produce wildly wrong estimates. Both conditions must
+// be met. The zero Warmup displays the ETA as soon as any progress
+// has been made.
+type Warmup struct {
+    // Elapsed is the time that must have passed since the progress
+    // bar was shown.
+    Elapsed time.Duration
+
+    // Samples is the number of distinct values the progress bar must
+    // have been drawn with. It is limited by the capacity of the
+    // progress bar's history, see SetHistoryCapacity().
+    Samples int
+}
+
+// SetETAWarmup will set the minimum amount of progress made before the
+// ETA of the progress bar is displayed. Until then, State.ETA()
+// reports no estimate, and ETADecorator() displays "ETA --:--".
+func (pb *ProgressBar) SetETAWarmup(warmup Warmup) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    pb.warmup = warmup
+    pb.update()
+}
+
+// warmingUp will determine whether the state is still within the
+// warm-up period of its ETA.
+func (s State) warmingUp() bool {
+    return s.Elapsed() < s.Warmup.Elapsed || s.Samples < s.Warmup.Samples
+}
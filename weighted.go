@@ -0,0 +1,102 @@
+package progresscli
+
+import (
+    "math"
+    "time"
+)
+
+// AddWeighted will increment the progress bar by the specified number
+// of items, which together cost the specified amount of work, such as
+// bytes processed or seconds of compute. Once a progress bar has been
+// incremented this way, its ETA is computed from the rate at which
+// cost accumulates instead of the rate at which items are completed,
+// which is more accurate for jobs whose items vary in size by orders
+// of magnitude. Set the expected total cost using SetTotalCost();
+// otherwise the remaining items are assumed to cost as much as the
+// average item so far.
+func (pb *ProgressBar) AddWeighted(items, cost float64) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    if pb.finished || !pb.visible {
+        return
+    }
+
+    pb.cost += cost
+    pb.value += items
+    pb.update()
+}
+
+// SetTotalCost will set the expected total cost of the items of the
+// progress bar, see AddWeighted(). Zero, the default, estimates it
+// from the average cost of the items completed so far.
+func (pb *ProgressBar) SetTotalCost(total float64) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    pb.totalCost = total
+    pb.update()
+}
+
+// addCost will add cost to the progress bar without completing any
+// items, for progress made on items that are still in progress.
+func (pb *ProgressBar) addCost(cost float64) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    if pb.finished || !pb.visible {
+        return
+    }
+
+    pb.cost += cost
+    pb.update()
+}
+
+// Cost will retrieve the cost accumulated using AddWeighted().
+func (pb *ProgressBar) Cost() float64 {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    return pb.cost
+}
+
+// costRate will compute the rate at which cost accumulates per second
+// over the samples in the progress bar's history. The caller must hold
+// pb.mu.
+func (pb *ProgressBar) costRate() float64 {
+    if pb.history.len() == 0 {
+        return 0
+    }
+
+    oldest := pb.history.oldest()
+    elapsed := pb.since(oldest.at).Seconds()
+    if elapsed <= 0 {
+        return 0
+    }
+
+    return (pb.cost - oldest.cost) / elapsed
+}
+
+// weightedETA will estimate the time remaining until the progress bar
+// is complete from the rate at which cost accumulates. If no cost has
+// accumulated yet, or neither an item has been completed nor the total
+// cost is known, false is returned.
+func (s State) weightedETA(done, remaining float64) (time.Duration, bool) {
+    elapsed := s.Elapsed().Seconds()
+    if s.Cost <= 0 || elapsed <= 0 || (done <= 0 && s.TotalCost <= 0) {
+        return 0, false
+    }
+
+    remainingCost := remaining * s.Cost / done
+    if s.TotalCost > 0 {
+        remainingCost = math.Max(s.TotalCost-s.Cost, 0)
+    }
+
+    rate := s.CostRate
+    if rate <= 0 {
+        rate = s.Cost / elapsed
+    }
+
+    seconds := remainingCost / rate
+    return time.Duration(seconds * float64(time.Second)), true
+}
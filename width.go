@@ -0,0 +1,228 @@
+package progresscli
+
+import (
+    "strings"
+    "unicode"
+    "unicode/utf8"
+)
+
+// wideRanges holds the ranges of characters that are displayed two
+// columns wide, mostly the East Asian wide and fullwidth characters
+// and emoji.
+var wideRanges = []struct{ lo, hi rune }{
+    {0x1100, 0x115f},
+    {0x231a, 0x231b},
+    {0x2329, 0x232a},
+    {0x23e9, 0x23ec},
+    {0x23f0, 0x23f0},
+    {0x23f3, 0x23f3},
+    {0x25fd, 0x25fe},
+    {0x2614, 0x2615},
+    {0x2648, 0x2653},
+    {0x267f, 0x267f},
+    {0x2693, 0x2693},
+    {0x26a1, 0x26a1},
+    {0x26aa, 0x26ab},
+    {0x26bd, 0x26be},
+    {0x26c4, 0x26c5},
+    {0x26ce, 0x26ce},
+    {0x26d4, 0x26d4},
+    {0x26ea, 0x26ea},
+    {0x26f2, 0x26f3},
+    {0x26f5, 0x26f5},
+    {0x26fa, 0x26fa},
+    {0x26fd, 0x26fd},
+    {0x2705, 0x2705},
+    {0x270a, 0x270b},
+    {0x2728, 0x2728},
+    {0x274c, 0x274c},
+    {0x274e, 0x274e},
+    {0x2753, 0x2755},
+    {0x2757, 0x2757},
+    {0x2795, 0x2797},
+    {0x27b0, 0x27b0},
+    {0x27bf, 0x27bf},
+    {0x2b1b, 0x2b1c},
+    {0x2b50, 0x2b50},
+    {0x2b55, 0x2b55},
+    {0x2e80, 0x303e},
+    {0x3041, 0x33ff},
+    {0x3400, 0x4dbf},
+    {0x4e00, 0x9fff},
+    {0xa000, 0xa4cf},
+    {0xa960, 0xa97f},
+    {0xac00, 0xd7a3},
+    {0xf900, 0xfaff},
+    {0xfe10, 0xfe19},
+    {0xfe30, 0xfe6f},
+    {0xff00, 0xff60},
+    {0xffe0, 0xffe6},
+    {0x16fe0, 0x16fe4},
+    {0x17000, 0x18cff},
+    {0x1b000, 0x1b2ff},
+    {0x1f004, 0x1f004},
+    {0x1f0cf, 0x1f0cf},
+    {0x1f18e, 0x1f18e},
+    {0x1f191, 0x1f19a},
+    {0x1f200, 0x1f202},
+    {0x1f210, 0x1f23b},
+    {0x1f240, 0x1f248},
+    {0x1f250, 0x1f251},
+    {0x1f260, 0x1f265},
+    {0x1f300, 0x1f320},
+    {0x1f32d, 0x1f335},
+    {0x1f337, 0x1f37c},
+    {0x1f37e, 0x1f393},
+    {0x1f3a0, 0x1f3ca},
+    {0x1f3cf, 0x1f3d3},
+    {0x1f3e0, 0x1f3f0},
+    {0x1f3f4, 0x1f3f4},
+    {0x1f3f8, 0x1f43e},
+    {0x1f440, 0x1f440},
+    {0x1f442, 0x1f4fc},
+    {0x1f4ff, 0x1f53d},
+    {0x1f54b, 0x1f54e},
+    {0x1f550, 0x1f567},
+    {0x1f57a, 0x1f57a},
+    {0x1f595, 0x1f596},
+    {0x1f5a4, 0x1f5a4},
+    {0x1f5fb, 0x1f64f},
+    {0x1f680, 0x1f6c5},
+    {0x1f6cc, 0x1f6cc},
+    {0x1f6d0, 0x1f6d2},
+    {0x1f6d5, 0x1f6d7},
+    {0x1f6eb, 0x1f6ec},
+    {0x1f6f4, 0x1f6fc},
+    {0x1f7e0, 0x1f7eb},
+    {0x1f90c, 0x1f93a},
+    {0x1f93c, 0x1f945},
+    {0x1f947, 0x1f9ff},
+    {0x1fa70, 0x1faff},
+    {0x20000, 0x2fffd},
+    {0x30000, 0x3fffd},
+}
+
+// VisibleWidth will measure the number of terminal columns s takes up
+// when displayed. ANSI escape sequences, including OSC 8 hyperlinks
+// apart from their text, take up no columns, East Asian wide
+// characters and emoji take up two columns and combining marks take up
+// none. This is the measurement used to lay out progress bars, so it
+// can be used to align other output with them.
+func VisibleWidth(s string) int {
+    width := 0
+    for _, r := range ansi_re.ReplaceAllString(s, "") {
+        width += runeWidth(r)
+    }
+
+    return width
+}
+
+// runeWidth will determine the number of columns a character takes up
+// when displayed.
+func runeWidth(r rune) int {
+    switch {
+    case r == 0x200b || r == 0x200d:
+        return 0
+    case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+        return 0
+    case r < 0x1100:
+        return 1
+    }
+
+    lo, hi := 0, len(wideRanges)-1
+    for lo <= hi {
+        mid := (lo + hi) / 2
+        switch {
+        case r < wideRanges[mid].lo:
+            hi = mid - 1
+        case r > wideRanges[mid].hi:
+            lo = mid + 1
+        default:
+            return 2
+        }
+    }
+
+    return 1
+}
+
+// TruncateToWidth will cut s down to at most width columns, measured
+// like VisibleWidth(), replacing the part that was cut off with
+// ellipsis. Escape sequences are kept and take up no columns, wide
+// characters are never split, and if any text was cut off the colors
+// are reset and hyperlinks closed so they don't leak into the
+// following text. If s fits, it is returned as it is. Decorators can
+// use it to fit their output into the space they have been given.
+func TruncateToWidth(s string, width int, ellipsis string) string {
+    if VisibleWidth(s) <= width {
+        return s
+    }
+
+    budget := width - VisibleWidth(ellipsis)
+    if budget < 0 {
+        ellipsis, budget = "", width
+    }
+
+    var (
+        output strings.Builder
+        styled bool
+        linked bool
+        used   int
+    )
+
+    escapes := ansi_re.FindAllStringIndex(s, -1)
+    for i := 0; i < len(s); {
+        if len(escapes) > 0 && escapes[0][0] == i {
+            escape := s[i:escapes[0][1]]
+            if strings.HasPrefix(escape, "\033]8;") {
+                linked = escape != "\033]8;;\033\\" &&
+                         escape != "\033]8;;\007"
+            } else {
+                styled = true
+            }
+
+            output.WriteString(escape)
+            i, escapes = escapes[0][1], escapes[1:]
+            continue
+        }
+
+        r, size := utf8.DecodeRuneInString(s[i:])
+        if used+runeWidth(r) > budget {
+            break
+        }
+
+        output.WriteString(s[i : i+size])
+        used += runeWidth(r)
+        i += size
+    }
+
+    if linked {
+        output.WriteString("\033]8;;\033\\")
+    }
+    if styled {
+        output.WriteString("\033[0m")
+    }
+
+    output.WriteString(ellipsis)
+    return output.String()
+}
+
+// padLeft will pad s with spaces on the left so that it takes up at
+// least width columns.
+func padLeft(s string, width int) string {
+    if pad := width - VisibleWidth(s); pad > 0 {
+        s = strings.Repeat(" ", pad) + s
+    }
+
+    return s
+}
+
+// fitWidth will pad s with spaces, or truncate it, so that it takes up
+// exactly width columns.
+func fitWidth(s string, width int) string {
+    s = TruncateToWidth(s, width, "…")
+    if pad := width - VisibleWidth(s); pad > 0 {
+        s += strings.Repeat(" ", pad)
+    }
+
+    return s
+}
@@ -0,0 +1,67 @@
+package progresscli
+
+// Progress is the view of a progress bar passed to the function run by
+// With(). It allows the function to report its progress without being
+// able to show, finish or abort the progress bar, which With() takes
+// care of.
+type Progress interface {
+    Increment(count float64)
+    IncrementFailed(count float64)
+    Skip(count float64, reason string)
+    SetValue(value float64)
+    GetValue() float64
+    SetLabel(label string)
+    Println(a ...any)
+}
+
+// With will show a progress bar with the specified label and max value
+// using Show() for the duration of fn, which reports its progress
+// through p. The progress bar is always finalized: it is finished when
+// fn returns nil, and aborted with the completed section drawn using the
+// failed character of its style when fn returns an error or panics.
+// The error returned by fn is returned, and a panic is passed on once
+// the progress bar has been aborted. If total is zero, the progress
+// bar is indeterminate.
+func With(label string, total float64, fn func(p Progress) error) error {
+    pb := New()
+    pb.SetLabel(label)
+    pb.SetZeroMax(ZeroMaxIndeterminate)
+    if err := pb.SetMax(total); err != nil {
+        return err
+    }
+
+    pb.Show()
+
+    failed := true
+    defer func() {
+        if failed {
+            pb.fail()
+        }
+    }()
+
+    if err := fn(pb); err != nil {
+        return err
+    }
+
+    failed = false
+
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    pb.finish()
+    return nil
+}
+
+// fail will draw the completed section of the progress bar using the
+// failed character of its style, if it has one, and abort it.
+func (pb *ProgressBar) fail() {
+    pb.mu.Lock()
+    if pb.visible && !pb.finished && pb.style.FailedChar != "" {
+        pb.style.DoneChar = pb.style.FailedChar
+        pb.restyle()
+        pb.draw()
+    }
+    pb.mu.Unlock()
+
+    pb.Abort()
+}
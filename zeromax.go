@@ -0,0 +1,35 @@
+package progresscli
+
+// ZeroMax determines how a progress bar with a max value of zero is
+// displayed.
+type ZeroMax int
+
+const (
+    // ZeroMaxComplete treats a progress bar with a max value of zero
+    // as complete, since there is no work to be done. This is the
+    // default.
+    ZeroMaxComplete ZeroMax = iota
+
+    // ZeroMaxIndeterminate treats a progress bar with a max value of
+    // zero as having an unknown amount of work. The bar displays an
+    // animated marker instead of a fill and never finishes on its
+    // own. Its value is not constrained, so it can still be used to
+    // count progress.
+    ZeroMaxIndeterminate
+)
+
+// SetZeroMax will set how the progress bar is displayed while its max
+// value is zero.
+func (pb *ProgressBar) SetZeroMax(behavior ZeroMax) {
+    pb.mu.Lock()
+    defer pb.mu.Unlock()
+
+    pb.zeroMax = behavior
+    pb.update()
+}
+
+// indeterminate will determine whether the amount of work of the
+// progress bar is unknown. The caller must hold pb.mu.
+func (pb *ProgressBar) indeterminate() bool {
+    return pb.max == 0 && pb.zeroMax == ZeroMaxIndeterminate
+}